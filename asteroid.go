@@ -2,7 +2,6 @@ package main
 
 import (
 	"math"
-	"math/rand"
 )
 
 type AsteroidType int
@@ -19,16 +18,25 @@ type Asteroid struct {
 	Size         float64      `json:"size"`
 	OwnerID      int          `json:"owner_id"`
 	OwnedSurface float64      `json:"surface"`
+	IsWreck      bool         `json:"is_wreck"`
+	Vector       Position     `json:"vector"` // Velocity of fragments flying apart, slowed down every tick
+	Weight       float64      `json:"weight"` // Share of the base score, fragments split their parent's weight
+	Damage       float64      `json:"damage"` // Damage taken from shots, the asteroid splits when it gets too high
 }
 
 func NewAsteroid(m *Map) *Asteroid {
+	return NewAsteroidAt(m, RandomPosition(m))
+}
+
+func NewAsteroidAt(m *Map, position Position) *Asteroid {
 	a := &Asteroid{
 		ID:           len(m.Asteroids),
-		Position:     RandomPosition(m),
-		Type:         AsteroidType(rand.Intn(2)),
+		Position:     position,
+		Type:         AsteroidType(rng.Intn(2)),
 		Size:         RandomFloat(MinAsteroidSize, MaxAsteroidSize),
 		OwnerID:      -1,
 		OwnedSurface: 0,
+		Weight:       1,
 	}
 
 	m.Asteroids = append(m.Asteroids, a)
@@ -56,6 +64,8 @@ func NewAsteroidFromShip(m *Map, ship *Ship, asteroidType AsteroidType) *Asteroi
 		Size:         size,
 		OwnerID:      ship.PlayerID,
 		OwnedSurface: size * size * math.Pi,
+		IsWreck:      true,
+		Weight:       1,
 	}
 
 	m.Asteroids = append(m.Asteroids, a)
@@ -67,8 +77,16 @@ func UpdateAsteroidPositions(m *Map) {
 	globalY := m.perlin.Noise2D(0, float64(m.Round)*PerlinNoiseScale) * GlobalAsteroidMovementScale
 	globalSteering := Position{X: globalX, Y: globalY}
 
+	// Towed asteroids move only with their ship
+	towed := map[int]bool{}
+	for _, ship := range m.Ships {
+		if ship != nil && !ship.IsDestroyed && ship.TowingID != -1 {
+			towed[ship.TowingID] = true
+		}
+	}
+
 	for _, asteroid := range m.Asteroids {
-		if asteroid == nil {
+		if asteroid == nil || towed[asteroid.ID] {
 			continue
 		}
 
@@ -85,23 +103,34 @@ func UpdateAsteroidPositions(m *Map) {
 		individualSteering := Position{X: individualX, Y: individualY}
 
 		// Apply both steering vectors and update position
-		totalMovement := globalSteering.Add(individualSteering)
+		totalMovement := globalSteering.Add(individualSteering).Add(asteroid.Vector)
 		asteroid.Position = asteroid.Position.Add(totalMovement)
+		asteroid.Vector = asteroid.Vector.Scale(AsteroidFragmentDrag)
 	}
 }
 
 func MineAsteroid(m *Map, ship *Ship, asteroid *Asteroid) {
 	materialToRemove := float64(ShipMiningAmount)
-	currentMaterial := asteroid.Size * asteroid.Size * math.Pi * MaterialToSurfaceRatio
-
-	if materialToRemove > currentMaterial {
-		materialToRemove = currentMaterial
+	if asteroid.IsWreck {
+		materialToRemove = ShipSalvageAmount
 	}
 
+	materialRemoved := RemoveAsteroidMaterial(m, asteroid, materialToRemove)
+
 	if asteroid.Type == FuelAsteroid {
-		ship.Fuel += materialToRemove
+		ship.Fuel += materialRemoved
 	} else {
-		ship.Rock += int(materialToRemove)
+		ship.Rock += int(materialRemoved)
+	}
+}
+
+// RemoveAsteroidMaterial shrinks the asteroid by up to materialToRemove, keeping the owned surface ratio.
+// Fully depleted asteroids are removed from the map. Returns the amount of material actually removed.
+func RemoveAsteroidMaterial(m *Map, asteroid *Asteroid, materialToRemove float64) float64 {
+	currentMaterial := asteroid.Size * asteroid.Size * math.Pi * MaterialToSurfaceRatio
+
+	if materialToRemove > currentMaterial {
+		materialToRemove = currentMaterial
 	}
 
 	newMaterial := currentMaterial - materialToRemove
@@ -123,11 +152,80 @@ func MineAsteroid(m *Map, ship *Ship, asteroid *Asteroid) {
 			asteroid.OwnedSurface = newSurfaceArea * surfaceRatio
 		}
 	}
+
+	return materialToRemove
+}
+
+// DamageAsteroid removes material hit by a shot. Large asteroids that took enough damage split into fragments.
+func DamageAsteroid(m *Map, asteroid *Asteroid, damage float64) {
+	RemoveAsteroidMaterial(m, asteroid, damage*ShotAsteroidMaterialRatio)
+	if m.Asteroids[asteroid.ID] == nil {
+		return
+	}
+
+	asteroid.Damage += damage
+	if asteroid.Damage >= AsteroidSplitDamage && asteroid.Size >= AsteroidSplitMinSize {
+		SplitAsteroid(m, asteroid)
+	}
+}
+
+// SplitAsteroid replaces the asteroid with equally sized fragments flying apart. Every fragment
+// keeps the owner, the owned surface ratio and an equal share of the material and score weight.
+func SplitAsteroid(m *Map, asteroid *Asteroid) []*Asteroid {
+	m.Asteroids[asteroid.ID] = nil
+
+	count := float64(AsteroidSplitFragments)
+	size := asteroid.Size / math.Sqrt(count)
+	baseAngle := RandomFloat(0, 2*math.Pi)
+
+	fragments := make([]*Asteroid, 0, AsteroidSplitFragments)
+	for i := range AsteroidSplitFragments {
+		angle := baseAngle + 2*math.Pi*float64(i)/count
+		direction := Position{X: math.Cos(angle), Y: math.Sin(angle)}
+
+		fragment := &Asteroid{
+			ID:           len(m.Asteroids),
+			Position:     asteroid.Position.Add(direction.Scale(size)),
+			Type:         asteroid.Type,
+			Size:         size,
+			OwnerID:      asteroid.OwnerID,
+			OwnedSurface: asteroid.OwnedSurface / count,
+			IsWreck:      asteroid.IsWreck,
+			Vector:       asteroid.Vector.Add(direction.Scale(AsteroidFragmentSpeed)),
+			Weight:       asteroid.Weight / count,
+		}
+
+		m.Asteroids = append(m.Asteroids, fragment)
+		fragments = append(fragments, fragment)
+	}
+
+	return fragments
+}
+
+// FirstAsteroidOnLine returns the asteroid closest to `from` whose body intersects the segment
+// between the two positions, or nil if the line is clear. Asteroids containing `from` are ignored.
+func FirstAsteroidOnLine(m *Map, from, to Position) *Asteroid {
+	var blocker *Asteroid
+	for _, asteroid := range m.Asteroids {
+		if asteroid == nil || from.Distance(asteroid.Position) <= asteroid.Size {
+			continue
+		}
+		if asteroid.Position.DistanceToSegment(from, to) > asteroid.Size {
+			continue
+		}
+		if blocker == nil || from.Distance(asteroid.Position) < from.Distance(blocker.Position) {
+			blocker = asteroid
+		}
+	}
+	return blocker
 }
 
 func UpdateScores(m *Map) {
 	for _, p := range m.Players {
-		p.Score = 0
+		// Scores of eliminated players stay frozen
+		if p.EliminatedRound == -1 {
+			p.Score = p.ZoneScore
+		}
 	}
 
 	for _, asteroid := range m.Asteroids {
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GameConfig holds the options passed to the game through the `args` field in games.json.
+// Args are whitespace separated `key=value` pairs, e.g. `teams=py1,py2;rust,py3`.
+type GameConfig struct {
+	Teams       [][]string              `json:"teams,omitempty"`         // Player names grouped into teams, empty means everyone plays alone
+	EventChance float64                 `json:"event_chance,omitempty"`  // Chance per round that a new random event is announced
+	LineOfSight string                  `json:"line_of_sight,omitempty"` // Whether asteroids block shots: "", "block" or "absorb"
+	Generator   string                  `json:"generator,omitempty"`     // Name of the map generator, empty means "random"
+	Seed        int64                   `json:"seed,omitempty"`          // Seed of the random generator, 0 means a random seed
+	MapFile     string                  `json:"map,omitempty"`           // Path to a hand-authored map, replaces the generator
+	ExportMap   string                  `json:"export_map,omitempty"`    // Path where the generated map is saved at the start of the game
+	Regions     map[string]RegionConfig `json:"regions,omitempty"`       // Overrides of the clustered generator regions
+	Zones       int                     `json:"zones,omitempty"`         // Number of control zones, 0 disables them
+	ZoneSpeed   float64                 `json:"zone_speed,omitempty"`    // Distance control zones drift every round
+	Elimination bool                    `json:"elimination,omitempty"`   // Motherships can be destroyed, which eliminates their player
+}
+
+func ParseGameConfig(args string) (GameConfig, []error) {
+	var config GameConfig
+	var errs []error
+
+	for _, field := range strings.Fields(args) {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			errs = append(errs, fmt.Errorf("invalid game argument '%v': expected key=value", field))
+			continue
+		}
+
+		switch key {
+		case "teams":
+			teams, err := parseTeams(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid teams '%v': %w", value, err))
+				continue
+			}
+			config.Teams = teams
+		case "events":
+			chance, err := strconv.ParseFloat(value, 64)
+			if err != nil || chance < 0 || chance > 1 {
+				errs = append(errs, fmt.Errorf("invalid event chance '%v': expected number between 0 and 1", value))
+				continue
+			}
+			config.EventChance = chance
+		case "line_of_sight":
+			if value != "block" && value != "absorb" && value != "off" {
+				errs = append(errs, fmt.Errorf("invalid line of sight mode '%v': expected block, absorb or off", value))
+				continue
+			}
+			if value == "off" {
+				value = ""
+			}
+			config.LineOfSight = value
+		case "generator":
+			if _, ok := mapGenerators[value]; !ok {
+				errs = append(errs, fmt.Errorf("unknown map generator '%v'", value))
+				continue
+			}
+			config.Generator = value
+		case "seed":
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid seed '%v': expected integer", value))
+				continue
+			}
+			config.Seed = seed
+		case "map":
+			config.MapFile = value
+		case "export_map":
+			config.ExportMap = value
+		case "zones":
+			zones, err := strconv.Atoi(value)
+			if err != nil || zones < 0 {
+				errs = append(errs, fmt.Errorf("invalid zone count '%v'", value))
+				continue
+			}
+			config.Zones = zones
+		case "zone_speed":
+			speed, err := strconv.ParseFloat(value, 64)
+			if err != nil || speed < 0 {
+				errs = append(errs, fmt.Errorf("invalid zone speed '%v'", value))
+				continue
+			}
+			config.ZoneSpeed = speed
+		case "elimination":
+			elimination, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid elimination flag '%v': expected true or false", value))
+				continue
+			}
+			config.Elimination = elimination
+		case "void", "belt", "nebula", "giant", "scatter":
+			region, err := parseRegion(config.Region(key), value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %v region '%v': %w", key, value, err))
+				continue
+			}
+			if config.Regions == nil {
+				config.Regions = map[string]RegionConfig{}
+			}
+			config.Regions[key] = region
+		default:
+			errs = append(errs, fmt.Errorf("unknown game argument: %v", key))
+		}
+	}
+
+	if len(config.Regions) > 0 && config.Generator != "clustered" {
+		errs = append(errs, fmt.Errorf("region overrides need generator=clustered"))
+	}
+
+	return config, errs
+}
+
+func parseTeams(value string) ([][]string, error) {
+	var teams [][]string
+	seen := map[string]bool{}
+	for _, team := range strings.Split(value, ";") {
+		var members []string
+		for _, name := range strings.Split(team, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("player %v is listed more than once", name)
+			}
+			seen[name] = true
+			members = append(members, name)
+		}
+		if len(members) > 0 {
+			teams = append(teams, members)
+		}
+	}
+	return teams, nil
+}
+
+// parseRegion applies comma separated `key:value` pairs to the region,
+// e.g. `regions:4,asteroids:30,fuel:0.2,size:35-50,radius:1000-2000,weight:2`
+func parseRegion(region RegionConfig, value string) (RegionConfig, error) {
+	for _, option := range strings.Split(value, ",") {
+		key, value, found := strings.Cut(option, ":")
+		if !found {
+			return region, fmt.Errorf("expected key:value, got '%v'", option)
+		}
+
+		var err error
+		switch key {
+		case "regions":
+			region.Regions, err = strconv.Atoi(value)
+		case "asteroids":
+			region.Asteroids, err = strconv.Atoi(value)
+		case "fuel":
+			region.FuelRatio, err = strconv.ParseFloat(value, 64)
+		case "weight":
+			region.Weight, err = strconv.ParseFloat(value, 64)
+			if err == nil && region.Weight <= 0 {
+				err = fmt.Errorf("weight must be positive")
+			}
+		case "size":
+			region.MinSize, region.MaxSize, err = parseRange(value)
+		case "radius":
+			region.MinRadius, region.MaxRadius, err = parseRange(value)
+		default:
+			return region, fmt.Errorf("unknown option '%v'", key)
+		}
+		if err != nil {
+			return region, fmt.Errorf("invalid %v '%v'", key, value)
+		}
+	}
+
+	if region.Regions < 0 || region.Asteroids < 0 || region.FuelRatio < 0 || region.FuelRatio > 1 || region.MinSize <= 0 && region.Asteroids > 0 {
+		return region, fmt.Errorf("values out of range")
+	}
+	return region, nil
+}
+
+// parseRange parses `min-max` or a single number used as both bounds
+func parseRange(value string) (float64, float64, error) {
+	from, to, found := strings.Cut(value, "-")
+	if !found {
+		to = from
+	}
+
+	low, err := strconv.ParseFloat(from, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	high, err := strconv.ParseFloat(to, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if low > high {
+		return 0, 0, fmt.Errorf("minimum is greater than maximum")
+	}
+	return low, high, nil
+}
+
+func (c GameConfig) TeamMode() bool {
+	return len(c.Teams) > 0
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"math"
 	"math/rand"
+	"time"
 )
 
 const (
@@ -35,10 +36,112 @@ const (
 	ShipMiningAmount                = 10                      // Units mined per tick
 	ShipConqueringDistance          = MaxAsteroidSize         // Maximum distance for conquering operations
 	ShipConqueringRate              = 10                      // Surface units conquered/lost per tick
+	EventWarningRounds              = 10                      // Number of rounds an event is announced before it starts
+	EventMinDuration                = 20                      // Minimum number of rounds an event lasts
+	EventMaxDuration                = 60                      // Maximum number of rounds an event lasts
+	EventMinRadius                  = 1000                    // Minimum radius of regional events
+	EventMaxRadius                  = 3000                    // Maximum radius of regional events
+	SolarStormGlobalChance          = 0.3                     // Chance that a solar storm covers the whole map
+	SolarStormDamage                = 2                       // Damage per round dealt by solar storms to uncovered ships
+	SolarStormCoverDistance         = 20                      // Distance from asteroid surface within which ships are covered from solar storms
+	MeteorShowerAsteroidCount       = 15                      // Number of asteroids spawned by a meteor shower
+	IonCloudMovementMultiplier      = 2.0                     // Fuel cost multiplier for movement inside ion clouds
+	MineRockCost                    = 20                      // Rock taken from the ship's cargo to lay a mine
+	MineTriggerDistance             = 30                      // Distance within which an enemy ship detonates a mine
+	MineBlastRadius                 = 60                      // Radius of the area damaged by a mine detonation
+	MineDamage                      = 40                      // Damage dealt to every ship in the blast radius
+	MineVisibilityDistance          = 100                     // Distance within which enemy ships can see a mine
+	ScoutShipMaxHealth              = 40                      // Maximum health points for scout ships
+	ScoutSensorRange                = 1000                    // Distance within which scouts detect cloaked ships and enemy mines
+	ScoutCloakFuelCost              = 2                       // Fuel paid by a cloaked ship every round
+	ShipSalvageAmount               = 20                      // Units salvaged per tick from wreck asteroids
+	DecommissionRefundRatio         = 0.5                     // Part of the ship price refunded when it is decommissioned
+	ShipShootCooldown               = 3                       // Counted down in the shot's round too, so the ship waits 2 rounds
+	ShipStartAmmo                   = 10                      // Ammo of a newly bought BattleShip
+	ShipMaxAmmo                     = 50                      // Maximum ammo a ship can carry
+	AmmoRockPrice                   = 2                       // Rock cost of a single unit of ammo
+	ShipShotHeat                    = 25                      // Heat generated by a single shot
+	ShipMaxHeat                     = 100                     // Ship can't shoot if the shot would raise its heat above this
+	ShipHeatDissipation             = 10                      // Heat lost by a ship every round
+	ShotAsteroidMaterialRatio       = 1.0                     // Asteroid material removed per point of absorbed shot damage
+	AsteroidSplitDamage             = 100                     // Damage after which an asteroid splits into fragments
+	AsteroidSplitMinSize            = MinAsteroidSize         // Smaller asteroids don't split, they are only worn down
+	AsteroidSplitFragments          = 3                       // Number of fragments an asteroid splits into
+	AsteroidFragmentSpeed           = 5.0                     // Initial speed of fragments flying apart
+	AsteroidFragmentDrag            = 0.95                    // Fraction of fragment speed kept every tick
+	ShipTowDistance                 = MaxAsteroidSize * 2     // Maximum distance for latching onto an asteroid
+	TowFuelPerSize                  = 0.02                    // Fuel per unit of distance and asteroid size paid while towing
+	ShipBoardingDistance            = ShipTransferDistance    // Maximum distance between the boarding ship and its target
+	ShipBoardingHealthThreshold     = 30                      // Ships with more health can't be boarded
+	ShipBoardingRounds              = 5                       // Rounds the boarding ship has to stay in range to capture the target
+	MinMothershipDistance           = 5000                    // Minimum distance between generated motherships
+	SpawnPlacementAttempts          = 100                     // Attempts to find a spawn far enough from the others before giving up
+	SpawnResourceRadius             = 2000                    // Radius around spawns within which resource counts are balanced
+	SymmetricSpawnDistance          = Radius / 2              // Distance of motherships from the map center in symmetric layouts
+	RegionPlacementAttempts         = 20                      // Attempts to place a clustered asteroid outside of voids
+	BeltWidth                       = 300                     // Maximum distance of belt asteroids from the belt axis
+	ZoneRadius                      = 1000                    // Radius of control zones
+	ZoneSpawnRatio                  = 0.5                     // Control zones are placed within this part of the map radius around the center
+	ZonePoints                      = 5                       // Points per round for holding a control zone
+	ZoneDominanceRatio              = 1.5                     // Presence needed to hold a zone compared to the strongest enemy, otherwise it is contested
+	MotherShipMaxHealth             = 1000                    // Maximum health points for motherships in elimination mode
+	EliminationPlacementBonus       = 1000                    // Final score bonus for every player or team eliminated before you
+	NavigationDefaultSpeed          = 100.0                   // Cruise speed of the autopilot if the order doesn't set one
+	NavigationArrivalDistance       = 10                      // Distance from the target at which the autopilot considers the ship arrived
+	NavigationTolerance             = 0.01                    // Smaller corrections of the movement vector are skipped
+	FleetDefaultSpacing             = 30.0                    // Distance between ships in a formation if the fleet doesn't set one
+	PlayerMaxFleets                 = 16                      // Maximum number of fleets a player can have
+	OrderDefaultAmount              = 100                     // Cargo carried by standing orders if the order doesn't set an amount
+	OrderFuelReserve                = 50                      // Fuel standing orders never unload, so the ship can keep flying
+	OrderSpeed                      = 20.0                    // Cruise speed of ships executing standing orders
+	MessageMaxSize                  = 1024                    // Maximum size of a message payload in bytes
+	PlayerMaxMessages               = 10                      // Maximum number of messages a player can send per round
+	DebugMaxShapes                  = 500                     // Maximum number of debug shapes a player can send per round
+	DebugMaxTextLength              = 100                     // Maximum length of debug labels and colors
+	ProtocolVersion                 = 1                       // Version of the bot protocol announced in the handshake
 )
 
 func ShipRockPrice(t ShipType) int {
-	return 250
+	switch t {
+	case ScoutShip:
+		return 100
+	default:
+		return 250
+	}
+}
+
+// ShipActionPoints is the action point budget the ship gets every round
+func ShipActionPoints(t ShipType) int {
+	switch t {
+	case MotherShip, TankerShip, TruckShip, BattleShip:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// TurnActionCost is the number of action points the turn spends on the ship
+func TurnActionCost(t TurnType) int {
+	switch t {
+	case BuyTurn, FleetTurn, FleetMoveTurn, FleetAttackTurn, TransactionTurn, MessageTurn:
+		// Don't use a ship, or spend the points of the turns they expand to
+		return 0
+	case ShootTurn, ShootAsteroidTurn, MineTurn, BoardTurn:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func ShipHealth(t ShipType) int {
+	switch t {
+	case MotherShip:
+		return MotherShipMaxHealth
+	case ScoutShip:
+		return ScoutShipMaxHealth
+	default:
+		return ShipMaxHealth
+	}
 }
 
 func ShipMovementFree(t ShipType) float64 {
@@ -60,6 +163,8 @@ func ShipMovementMultiplier(t ShipType) float64 {
 		fallthrough
 	case TankerShip:
 		return BaseShipMovementMultiplier / 3.0
+	case ScoutShip:
+		return BaseShipMovementMultiplier / 5.0
 	default:
 		return BaseShipMovementMultiplier
 	}
@@ -69,13 +174,20 @@ func ShipMovementPrice(vector Position, t ShipType) float64 {
 	return max(0.0, (vector.Size()-ShipMovementFree(t))*ShipMovementMultiplier(t))
 }
 
+// rng is the source of all randomness in the game, it can be seeded to replay the same map
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func SeedRandom(seed int64) {
+	rng = rand.New(rand.NewSource(seed))
+}
+
 func RandomFloat(min, max float64) float64 {
-	return rand.Float64()*(max-min) + min
+	return rng.Float64()*(max-min) + min
 }
 
 func AsteroidScore(a Asteroid) float64 {
 	asteroidSurface := float64(a.Size * a.Size * math.Pi)
 	ownedSurface := float64(a.OwnedSurface)
 	surfaceFactor := math.Pow(1.5, (ownedSurface/asteroidSurface*100)/9.0) * (a.Size / float64(MaxAsteroidSize))
-	return 50 + surfaceFactor
+	return 50*a.Weight + surfaceFactor
 }
@@ -0,0 +1,80 @@
+package main
+
+import "fmt"
+
+type DebugShapeType string
+
+const (
+	DebugLine   DebugShapeType = "line"
+	DebugCircle DebugShapeType = "circle"
+	DebugText   DebugShapeType = "text"
+)
+
+// DebugShape is an annotation a bot attaches to its response. It isn't used by the game,
+// the observer only draws it for the round in which it was sent.
+type DebugShape struct {
+	Type     DebugShapeType `json:"type"`
+	Position Position       `json:"position"`             // Start of a line, center of a circle or anchor of a text
+	ShipID   *int           `json:"ship_id,omitempty"`    // Ship whose position is used instead of Position
+	To       *Position      `json:"to,omitempty"`         // End of a line
+	ToShipID *int           `json:"to_ship_id,omitempty"` // Ship whose position is used as the end of a line
+	Radius   float64        `json:"radius,omitempty"`     // Radius of a circle
+	Text     string         `json:"text,omitempty"`       // Text of a label
+	Color    string         `json:"color,omitempty"`      // CSS color, the player's color by default
+}
+
+func (s DebugShape) Validate(m *Map) error {
+	for _, id := range []*int{s.ShipID, s.ToShipID} {
+		if id != nil && (*id < 0 || *id >= len(m.Ships)) {
+			return fmt.Errorf("invalid ship id: %v", *id)
+		}
+	}
+	if len(s.Text) > DebugMaxTextLength {
+		return fmt.Errorf("text too long: %v > %v", len(s.Text), DebugMaxTextLength)
+	}
+	if len(s.Color) > DebugMaxTextLength {
+		return fmt.Errorf("color too long: %v > %v", len(s.Color), DebugMaxTextLength)
+	}
+
+	switch s.Type {
+	case DebugLine:
+		if s.To == nil && s.ToShipID == nil {
+			return fmt.Errorf("line needs an end")
+		}
+	case DebugCircle:
+		if s.Radius <= 0 {
+			return fmt.Errorf("circle radius must be positive: %v", s.Radius)
+		}
+	case DebugText:
+		if s.Text == "" {
+			return fmt.Errorf("text can't be empty")
+		}
+	default:
+		return fmt.Errorf("unknown debug shape: %v", s.Type)
+	}
+	return nil
+}
+
+// StoreDebugShapes keeps the valid shapes sent by the player for the observer, invalid ones are skipped
+func StoreDebugShapes(m *Map, p *Player, shapes []DebugShape) {
+	if len(shapes) > DebugMaxShapes {
+		m.runner.Log(fmt.Sprintf("too many debug shapes from player %v: %v > %v", p.Name, len(shapes), DebugMaxShapes))
+		shapes = shapes[:DebugMaxShapes]
+	}
+
+	for _, shape := range shapes {
+		if err := shape.Validate(m); err != nil {
+			m.runner.Log(fmt.Sprintf("invalid debug shape from player %v: %v", p.Name, err))
+			continue
+		}
+		m.Debug[p.ID] = append(m.Debug[p.ID], shape)
+	}
+}
+
+// ResetDebugShapes drops the shapes of the previous round
+func ResetDebugShapes(m *Map) {
+	m.Debug = make([][]DebugShape, len(m.Players))
+	for i := range m.Debug {
+		m.Debug[i] = []DebugShape{}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// DeltaStates capability replaces the full map in game states by the changes since the previous state
+const DeltaStates Capability = "delta_states"
+
+// MapDelta carries the changes of the player's view since the previous state. Static data
+// (radius and wormholes) is only sent in full states, small lists are always sent whole.
+type MapDelta struct {
+	Round            int         `json:"round"`
+	Ships            []*Ship     `json:"ships"`             // New or changed ships
+	RemovedShips     []int       `json:"removed_ships"`     // Ships which are no longer visible
+	Asteroids        []*Asteroid `json:"asteroids"`         // New or changed asteroids
+	RemovedAsteroids []int       `json:"removed_asteroids"` // Asteroids which were depleted
+	Players          []*Player   `json:"players"`           // Changed players
+	Events           []*Event    `json:"events"`
+	Mines            []*Mine     `json:"mines"`
+	Zones            []*Zone     `json:"zones"`
+}
+
+// deltaBase is the view last sent to the player, deltas are computed against it
+type deltaBase struct {
+	ships     map[int]Ship
+	asteroids map[int]Asteroid
+	players   map[int]string
+}
+
+func newDeltaBase(view *Map) *deltaBase {
+	base := &deltaBase{ships: map[int]Ship{}, asteroids: map[int]Asteroid{}, players: map[int]string{}}
+	for _, ship := range view.Ships {
+		if ship != nil {
+			base.ships[ship.ID] = copyShip(ship)
+		}
+	}
+	for _, asteroid := range view.Asteroids {
+		if asteroid != nil {
+			base.asteroids[asteroid.ID] = *asteroid
+		}
+	}
+	for _, player := range view.Players {
+		base.players[player.ID] = playerJSON(player)
+	}
+	return base
+}
+
+// copyShip copies the ship together with its orders, which are changed in place
+func copyShip(ship *Ship) Ship {
+	s := *ship
+	if s.Navigation != nil {
+		navigation := *s.Navigation
+		s.Navigation = &navigation
+	}
+	if s.Order != nil {
+		order := *s.Order
+		s.Order = &order
+	}
+	return s
+}
+
+func shipsEqual(a Ship, b *Ship) bool {
+	if (a.Navigation == nil) != (b.Navigation == nil) || (a.Navigation != nil && *a.Navigation != *b.Navigation) {
+		return false
+	}
+	if (a.Order == nil) != (b.Order == nil) || (a.Order != nil && *a.Order != *b.Order) {
+		return false
+	}
+	a.Navigation, a.Order = b.Navigation, b.Order
+	return a == *b
+}
+
+func playerJSON(p *Player) string {
+	data, err := json.Marshal(p)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// NewMapDelta returns the changes between the previously sent view and the current one
+func NewMapDelta(base *deltaBase, view *Map) *MapDelta {
+	delta := &MapDelta{
+		Round:            view.Round,
+		Ships:            []*Ship{},
+		RemovedShips:     []int{},
+		Asteroids:        []*Asteroid{},
+		RemovedAsteroids: []int{},
+		Players:          []*Player{},
+		Events:           view.Events,
+		Mines:            view.Mines,
+		Zones:            view.Zones,
+	}
+
+	for id, ship := range view.Ships {
+		last, sent := base.ships[id]
+		if ship == nil && sent {
+			delta.RemovedShips = append(delta.RemovedShips, id)
+		} else if ship != nil && (!sent || !shipsEqual(last, ship)) {
+			delta.Ships = append(delta.Ships, ship)
+		}
+	}
+	for id, asteroid := range view.Asteroids {
+		last, sent := base.asteroids[id]
+		if asteroid == nil && sent {
+			delta.RemovedAsteroids = append(delta.RemovedAsteroids, id)
+		} else if asteroid != nil && (!sent || last != *asteroid) {
+			delta.Asteroids = append(delta.Asteroids, asteroid)
+		}
+	}
+	for _, player := range view.Players {
+		if base.players[player.ID] != playerJSON(player) {
+			delta.Players = append(delta.Players, player)
+		}
+	}
+
+	return delta
+}
+
+// StateHash sums up the player's view, clients compute it from their copy of the map to detect
+// a desync. It is the FNV-1a (64 bit, 16 hex digits) of "round:R;ships:S|S;asteroids:A|A;players:P|P"
+// with the entities in ascending ID order and their fields separated by commas:
+//   - ship: id, player, type, position x, y, vector x, y, health, fuel, rock, is_destroyed
+//   - asteroid: id, type, owner_id, position x, y, size, surface
+//   - player: id, alive, eliminated_round
+//
+// Numbers are written as integers, decimals with exactly 3 digits after the point and booleans as 0 or 1.
+func StateHash(view *Map) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "round:%d;ships:", view.Round)
+	writeEntities(&b, view.Ships, func(s *Ship) string {
+		return fmt.Sprintf("%d,%d,%d,%s,%s,%s,%s,%d,%s,%d,%d", s.ID, s.PlayerID, s.Type,
+			hashFloat(s.Position.X), hashFloat(s.Position.Y), hashFloat(s.Vector.X), hashFloat(s.Vector.Y),
+			s.Health, hashFloat(s.Fuel), s.Rock, hashBool(s.IsDestroyed))
+	})
+	b.WriteString(";asteroids:")
+	writeEntities(&b, view.Asteroids, func(a *Asteroid) string {
+		return fmt.Sprintf("%d,%d,%d,%s,%s,%s,%s", a.ID, a.Type, a.OwnerID,
+			hashFloat(a.Position.X), hashFloat(a.Position.Y), hashFloat(a.Size), hashFloat(a.OwnedSurface))
+	})
+	b.WriteString(";players:")
+	writeEntities(&b, view.Players, func(p *Player) string {
+		return fmt.Sprintf("%d,%d,%d", p.ID, hashBool(p.Alive), p.EliminatedRound)
+	})
+
+	h := fnv.New64a()
+	h.Write([]byte(b.String()))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func writeEntities[T any](b *strings.Builder, entities []*T, format func(*T) string) {
+	first := true
+	for _, entity := range entities {
+		if entity == nil {
+			continue
+		}
+		if !first {
+			b.WriteString("|")
+		}
+		b.WriteString(format(entity))
+		first = false
+	}
+}
+
+// hashFloat adds 0 to turn -0 into 0, clients parse "-0" from JSON as an integer 0
+func hashFloat(x float64) string {
+	return strconv.FormatFloat(x+0, 'f', 3, 64)
+}
+
+func hashBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
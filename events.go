@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+)
+
+type EventType int
+
+const (
+	SolarStorm EventType = iota
+	MeteorShower
+	IonCloud
+)
+
+// Event is a map-wide or regional hazard. Events are announced EventWarningRounds before they start.
+type Event struct {
+	ID         int       `json:"id"`
+	Type       EventType `json:"type"`
+	Position   Position  `json:"position"`
+	Radius     float64   `json:"radius"` // 0 means the event covers the whole map
+	StartRound int       `json:"start_round"`
+	EndRound   int       `json:"end_round"`
+}
+
+func (e *Event) Active(round int) bool {
+	return round >= e.StartRound && round <= e.EndRound
+}
+
+func (e *Event) Covers(position Position) bool {
+	return e.Radius == 0 || e.Position.Distance(position) <= e.Radius
+}
+
+// ActiveEventAt returns an active event of the given type covering the position, or nil
+func ActiveEventAt(m *Map, position Position, eventType EventType) *Event {
+	for _, event := range m.Events {
+		if event.Type == eventType && event.Active(m.Round) && event.Covers(position) {
+			return event
+		}
+	}
+	return nil
+}
+
+func NewEvent(m *Map) *Event {
+	e := &Event{
+		ID:         m.nextEventID,
+		Type:       EventType(rng.Intn(3)),
+		Position:   RandomPosition(m),
+		Radius:     RandomFloat(EventMinRadius, EventMaxRadius),
+		StartRound: m.Round + EventWarningRounds,
+	}
+	e.EndRound = e.StartRound + EventMinDuration + rng.Intn(EventMaxDuration-EventMinDuration+1)
+
+	if e.Type == SolarStorm && rng.Float64() < SolarStormGlobalChance {
+		e.Position = Position{}
+		e.Radius = 0
+	}
+	if e.Type == MeteorShower {
+		// Meteor showers only spawn asteroids once, they don't need to last
+		e.EndRound = e.StartRound
+	}
+
+	m.nextEventID++
+	m.Events = append(m.Events, e)
+	return e
+}
+
+// ScheduleEvents drops finished events and randomly announces new ones
+func ScheduleEvents(m *Map) {
+	events := m.Events[:0]
+	for _, event := range m.Events {
+		if event.EndRound >= m.Round {
+			events = append(events, event)
+		}
+	}
+	m.Events = events
+
+	if m.Config.EventChance > 0 && rng.Float64() < m.Config.EventChance {
+		e := NewEvent(m)
+		m.runner.Log(fmt.Sprintf("event %d of type %d announced for rounds %d-%d", e.ID, e.Type, e.StartRound, e.EndRound))
+	}
+}
+
+func ApplyEvents(m *Map) {
+	for _, event := range m.Events {
+		if !event.Active(m.Round) {
+			continue
+		}
+
+		switch event.Type {
+		case SolarStorm:
+			ApplySolarStorm(m, event)
+		case MeteorShower:
+			if event.StartRound == m.Round {
+				for range MeteorShowerAsteroidCount {
+					NewAsteroidAt(m, RandomOffsetPosition(event.Position, event.Radius))
+				}
+			}
+		}
+	}
+}
+
+// ApplySolarStorm damages every ship in the storm which is not hiding next to an asteroid
+func ApplySolarStorm(m *Map, event *Event) {
+	for _, ship := range m.Ships {
+		if ship == nil || ship.IsDestroyed || ship.Type == MotherShip || !event.Covers(ship.Position) {
+			continue
+		}
+		if IsCoveredByAsteroid(m, ship.Position) {
+			continue
+		}
+		ship.Health -= SolarStormDamage
+	}
+}
+
+func IsCoveredByAsteroid(m *Map, position Position) bool {
+	for _, asteroid := range m.Asteroids {
+		if asteroid == nil {
+			continue
+		}
+		if position.Distance(asteroid.Position) <= asteroid.Size+SolarStormCoverDistance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"math"
+	"slices"
+)
+
+type Formation string
+
+const (
+	NoFormation     Formation = ""
+	LineFormation   Formation = "line"
+	ColumnFormation Formation = "column"
+	WedgeFormation  Formation = "wedge"
+	CircleFormation Formation = "circle"
+)
+
+// Fleet is a named group of the player's ships which can receive group orders.
+// Fleets are private, every player only sees their own in the game state.
+type Fleet struct {
+	Name      string    `json:"name"`
+	ShipIDs   []int     `json:"ship_ids"`
+	Formation Formation `json:"formation"`
+	Spacing   float64   `json:"spacing"` // Distance between neighbouring ships in the formation
+}
+
+// PruneFleets removes ships which were destroyed or captured from the player's fleets
+func PruneFleets(m *Map, p *Player) {
+	for _, fleet := range p.Fleets {
+		fleet.ShipIDs = slices.DeleteFunc(fleet.ShipIDs, func(id int) bool {
+			ship := m.Ships[id]
+			return ship.IsDestroyed || ship.PlayerID != p.ID
+		})
+	}
+}
+
+// RemoveFromFleets takes the ship out of any fleet of the player, a ship belongs to at most one fleet
+func RemoveFromFleets(p *Player, shipID int) {
+	for _, fleet := range p.Fleets {
+		fleet.ShipIDs = slices.DeleteFunc(fleet.ShipIDs, func(id int) bool { return id == shipID })
+	}
+}
+
+// Center returns the average position of the fleet's ships
+func (f *Fleet) Center(m *Map) Position {
+	var center Position
+	for _, id := range f.ShipIDs {
+		center = center.Add(m.Ships[id].Position)
+	}
+	if len(f.ShipIDs) == 0 {
+		return center
+	}
+	return center.Scale(1 / float64(len(f.ShipIDs)))
+}
+
+// FormationOffset returns the position of the i-th ship relative to the fleet's target
+// when the fleet travels in the given direction
+func (f *Fleet) FormationOffset(i int, direction Position) Position {
+	count := len(f.ShipIDs)
+	forward := direction.Normalize()
+	if forward.Size() == 0 {
+		forward = Position{1, 0}
+	}
+	side := Position{-forward.Y, forward.X}
+
+	switch f.Formation {
+	case LineFormation:
+		return side.Scale((float64(i) - float64(count-1)/2) * f.Spacing)
+	case ColumnFormation:
+		return forward.Scale(-float64(i) * f.Spacing)
+	case WedgeFormation:
+		// Leader in front, the others alternate between the two arms of the wedge
+		row := float64((i + 1) / 2)
+		if i%2 == 1 {
+			row = -row
+		}
+		return forward.Scale(-math.Abs(row) * f.Spacing).Add(side.Scale(row * f.Spacing))
+	case CircleFormation:
+		if count < 2 {
+			return Position{}
+		}
+		radius := f.Spacing / (2 * math.Sin(math.Pi/float64(count)))
+		return Position{radius, 0}.Rotate(2 * math.Pi * float64(i) / float64(count))
+	default:
+		return Position{}
+	}
+}
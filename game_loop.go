@@ -9,18 +9,79 @@ import (
 )
 
 type GameState struct {
-	Map      *Map `json:"map"`
-	PlayerID int  `json:"player_id"`
+	Map      *Map              `json:"map,omitempty"` // Missing when Delta is sent instead
+	PlayerID int               `json:"player_id"`
+	Fleets   map[string]*Fleet `json:"fleets"`
+	Reports  []OrderReport     `json:"reports"`
+	Messages []*Message        `json:"messages"` // Messages sent to the player last round
+	// Transactions rolled back in the last round with the turn which failed
+	FailedTransactions []TransactionReport `json:"failed_transactions"`
+	// Sent only in the first round, see Handshake
+	Handshake *Handshake `json:"handshake,omitempty"`
+	// Capabilities the bot asked for which the server accepted
+	Capabilities []Capability `json:"capabilities,omitempty"`
+	// Changes since the previous state for bots with the delta_states capability, see MapDelta
+	Delta *MapDelta `json:"delta,omitempty"`
+	Hash  string    `json:"hash,omitempty"` // StateHash of the view, sent with delta states
 }
 
 type ObserverGameState struct {
 	Map *Map `json:"map"`
 }
 
+// MapViewFor returns a shallow copy of the map with the entities the player can't see removed
+func MapViewFor(m *Map, p *Player) *Map {
+	view := *m
+
+	view.Ships = make([]*Ship, len(m.Ships))
+	for i, ship := range m.Ships {
+		if ship != nil && ShipVisibleTo(m, ship, p) {
+			view.Ships[i] = ship
+		}
+	}
+
+	view.Mines = []*Mine{}
+	for _, mine := range m.Mines {
+		if MineVisibleTo(m, mine, p) {
+			view.Mines = append(view.Mines, mine)
+		}
+	}
+
+	// Messages are delivered separately, only to their recipients
+	view.Messages = nil
+	view.Debug = nil
+
+	return &view
+}
+
 func GameStateFor(m *Map, p *Player) string {
+	view := MapViewFor(m, p)
 	state := GameState{
-		Map:      m,
+		Map:      view,
 		PlayerID: p.ID,
+		Fleets:   p.Fleets,
+		Reports:  p.Reports,
+		Messages: MessagesFor(m, p),
+
+		FailedTransactions: p.FailedTransactions,
+		Capabilities:       p.Capabilities,
+	}
+	if m.Round == 0 {
+		state.Handshake = NewHandshake(m)
+	}
+	if p.HasCapability(DeltaStates) {
+		if p.deltaBase != nil {
+			state.Map = nil
+			state.Delta = NewMapDelta(p.deltaBase, view)
+		}
+		state.Hash = StateHash(view)
+		p.deltaBase = newDeltaBase(view)
+	}
+	if state.Reports == nil {
+		state.Reports = []OrderReport{}
+	}
+	if state.FailedTransactions == nil {
+		state.FailedTransactions = []TransactionReport{}
 	}
 	data, err := json.Marshal(state)
 	if err != nil {
@@ -40,7 +101,9 @@ func StateForObserver(m *Map) string {
 }
 
 func GameTick(m *Map) {
-	m.UsedShips = make(map[int]map[int]bool)
+	ResetActionPoints(m)
+	DeliverMessages(m)
+	ResetDebugShapes(m)
 	m.runner.Log(fmt.Sprintf("Round %v", m.Round))
 
 	for _, player := range m.Players {
@@ -48,7 +111,10 @@ func GameTick(m *Map) {
 			continue
 		}
 
+		PruneFleets(m, player)
 		state := GameStateFor(m, player)
+		player.Reports = nil
+		player.FailedTransactions = nil
 		resp := m.runner.ToPlayer(player.Name, fmt.Sprintf("round %v", m.Round), state)
 		if resp != client.Ok {
 			m.runner.Log(fmt.Sprintf("unexpected result of TO PLAYER operation for %v: %v", player.Name, resp))
@@ -63,18 +129,29 @@ func GameTick(m *Map) {
 			continue
 		}
 
-		var turns []TurnContainer
-		err := json.Unmarshal([]byte(data), &turns)
+		response, err := ParsePlayerResponse(data)
 		if err != nil {
 			m.runner.Log(fmt.Sprintf("invalid JSON from player %v: %v", player.Name, err))
 			continue
 		}
 
 		m.runner.Log(fmt.Sprintf("executing turns for %v", player.Name))
-		ExecuteTurns(m, player, turns)
+		if response.Capabilities != nil {
+			SetCapabilities(m, player, response.Capabilities)
+		}
+		if response.Resync {
+			player.deltaBase = nil
+		}
+		StoreDebugShapes(m, player, response.Debug)
+		ExecuteTurns(m, player, response.Turns)
+		StepOrders(m, player)
+		StepNavigation(m, player)
 		TickPlayerShips(m, player)
 	}
 
+	TriggerMines(m)
+	ApplyEvents(m)
+
 	// Check for ships that have reached 0 HP and mark them as destroyed
 	CheckAndMarkDestroyedShips(m)
 
@@ -93,9 +170,18 @@ func TickPlayerShips(m *Map, p *Player) {
 			continue
 		}
 
+		previousPosition := ship.Position
 		ship.Position = ship.Position.Add(ship.Vector)
 		CheckShipWormholeTeleportation(m, ship)
-		if ship.Type == DrillShip || ship.Type == SuckerShip {
+		HandleShipTowing(m, ship, previousPosition)
+		HandleShipBoarding(m, ship)
+		HandleShipWeapons(ship)
+		HandleShipCloak(m, ship)
+		if ship.Cloaked {
+			// Cloaked ships can't interact with the world
+			continue
+		}
+		if ship.Type != MotherShip {
 			HandleShipMining(m, ship)
 		}
 		HandleShipConquering(m, ship)
@@ -103,6 +189,10 @@ func TickPlayerShips(m *Map, p *Player) {
 }
 
 func CheckAsteroidType(ship *Ship, asteroid *Asteroid) bool {
+	// Wreckage can be salvaged by any ship
+	if asteroid.IsWreck && ship.Type != MotherShip {
+		return true
+	}
 	if ship.Type == SuckerShip && asteroid.Type == FuelAsteroid {
 		return true
 	}
@@ -143,7 +233,8 @@ func HandleShipConquering(m *Map, ship *Ship) {
 func ConquerAsteroid(m *Map, ship *Ship, asteroid *Asteroid) {
 	totalSurface := asteroid.Size * asteroid.Size * math.Pi
 
-	if asteroid.OwnerID == ship.PlayerID {
+	// Allies share their asteroids, so they reinforce each other's surface instead of conquering it
+	if m.Allied(asteroid.OwnerID, ship.PlayerID) {
 		asteroid.OwnedSurface = min(asteroid.OwnedSurface+ShipConqueringRate, totalSurface)
 	} else {
 		asteroid.OwnedSurface = max(asteroid.OwnedSurface-ShipConqueringRate, 0)
@@ -7,13 +7,35 @@ import (
 )
 
 func StartGame(runner client.Runner) *Map {
-	m := NewMap()
+	playerNames, args := runner.ReadConfig()
+	config, errs := ParseGameConfig(args)
+	for _, err := range errs {
+		runner.Log(fmt.Sprintf("ignoring game argument: %v", err))
+	}
+
+	generator, err := config.MapGenerator()
+	if err != nil {
+		runner.Log(fmt.Sprintf("using random map instead: %v", err))
+		generator = RandomGenerator{}
+	}
+
+	m := NewMap(config, generator, len(playerNames))
 	m.runner = &runner
-	playerNames, _ := runner.ReadConfig()
 
 	for _, name := range playerNames {
 		NewPlayer(m, name)
 	}
+	AssignTeams(m)
+
+	if ships, ok := generator.(ShipGenerator); ok {
+		ships.GenerateShips(m)
+	}
+
+	if config.ExportMap != "" {
+		if err := WriteMapFile(m, config.ExportMap); err != nil {
+			runner.Log(fmt.Sprintf("could not export map: %v", err))
+		}
+	}
 
 	runner.Log(fmt.Sprintf("game ready for %d players", len(m.Players)))
 
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Capability is an optional protocol extension a bot can ask for in its response.
+// The server ignores capabilities it doesn't support, so bots keep working with the plain protocol.
+type Capability string
+
+// SupportedCapabilities lists the capabilities this server understands
+var SupportedCapabilities = []Capability{DeltaStates}
+
+// ShipSpec describes a ship type for bots which don't want to hardcode the constants
+type ShipSpec struct {
+	Type               ShipType `json:"type"`
+	RockPrice          int      `json:"rock_price"`
+	Health             int      `json:"health"`
+	ActionPoints       int      `json:"action_points"`
+	MovementFree       float64  `json:"movement_free"`       // Movement which costs no fuel
+	MovementMultiplier float64  `json:"movement_multiplier"` // Fuel paid per unit of movement above the free part
+}
+
+// Handshake is attached to the first game state a bot receives. Bots which don't know it just ignore it.
+type Handshake struct {
+	ProtocolVersion int             `json:"protocol_version"`
+	Config          GameConfig      `json:"config"`
+	Ships           []ShipSpec      `json:"ships"`        // Indexed by ShipType
+	ActionCosts     []int           `json:"action_costs"` // Action points spent by each TurnType
+	Features        map[string]bool `json:"features"`     // Game rules enabled in this match
+	Capabilities    []Capability    `json:"capabilities"` // Protocol extensions the bot may ask for
+}
+
+func NewHandshake(m *Map) *Handshake {
+	// Don't reveal the random seed or paths on the server
+	config := m.Config
+	config.Seed = 0
+	config.MapFile = ""
+	config.ExportMap = ""
+
+	h := &Handshake{
+		ProtocolVersion: ProtocolVersion,
+		Config:          config,
+		Ships:           []ShipSpec{},
+		ActionCosts:     []int{},
+		Features: map[string]bool{
+			"teams":         len(m.Config.Teams) > 0,
+			"events":        m.Config.EventChance > 0,
+			"line_of_sight": m.Config.LineOfSight != "",
+			"zones":         m.Config.Zones > 0,
+			"elimination":   m.Config.Elimination,
+		},
+		Capabilities: SupportedCapabilities,
+	}
+
+	for t := MotherShip; t <= ScoutShip; t++ {
+		h.Ships = append(h.Ships, ShipSpec{
+			Type:               t,
+			RockPrice:          ShipRockPrice(t),
+			Health:             ShipHealth(t),
+			ActionPoints:       ShipActionPoints(t),
+			MovementFree:       ShipMovementFree(t),
+			MovementMultiplier: ShipMovementMultiplier(t),
+		})
+	}
+	// Motherships are indestructible without elimination, their health means nothing
+	if !m.Config.Elimination {
+		h.Ships[MotherShip].Health = 0
+	}
+	for t := BuyTurn; t <= lastTurnType; t++ {
+		h.ActionCosts = append(h.ActionCosts, TurnActionCost(t))
+	}
+
+	return h
+}
+
+// SetCapabilities enables the capabilities the bot asked for which the server supports
+func SetCapabilities(m *Map, p *Player, capabilities []Capability) {
+	p.Capabilities = []Capability{}
+	p.deltaBase = nil
+	for _, capability := range capabilities {
+		if !slices.Contains(SupportedCapabilities, capability) {
+			m.runner.Log(fmt.Sprintf("player %v asked for unsupported capability %v", p.Name, capability))
+			continue
+		}
+		if !slices.Contains(p.Capabilities, capability) {
+			p.Capabilities = append(p.Capabilities, capability)
+		}
+	}
+}
+
+func (p *Player) HasCapability(capability Capability) bool {
+	return slices.Contains(p.Capabilities, capability)
+}
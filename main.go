@@ -1,10 +1,21 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/trojsten/ksp-proboj/client"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export-map" {
+		if err := ExportMapCommand(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	runner := client.NewRunner()
 	m := StartGame(runner)
 
@@ -12,11 +23,6 @@ func main() {
 		GameTick(m)
 	}
 
-	scores := map[string]int{}
-	for _, p := range m.Players {
-		scores[p.Name] = p.Score
-	}
-
-	runner.Scores(scores)
+	runner.Scores(FinalScores(m))
 	runner.End()
 }
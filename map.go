@@ -1,45 +1,67 @@
 package main
 
 import (
-	"math/rand"
-
 	"github.com/aquilax/go-perlin"
 	"github.com/trojsten/ksp-proboj/client"
 )
 
 type Map struct {
-	Radius    float64              `json:"radius"`
-	Ships     []*Ship              `json:"ships"`
-	Asteroids []*Asteroid          `json:"asteroids"`
-	Wormholes []*Wormhole          `json:"wormholes"`
-	Players   []*Player            `json:"players"`
-	runner    *client.Runner       `json:"-"`
-	Round     int                  `json:"round"`
-	perlin    *perlin.Perlin       `json:"-"`
-	UsedShips map[int]map[int]bool `json:"-"` // playerID -> shipID -> hasBeenUsed
-}
+	Radius    float64        `json:"radius"`
+	Ships     []*Ship        `json:"ships"`
+	Asteroids []*Asteroid    `json:"asteroids"`
+	Wormholes []*Wormhole    `json:"wormholes"`
+	Players   []*Player      `json:"players"`
+	Events    []*Event       `json:"events"`
+	Mines     []*Mine        `json:"mines"`
+	Zones     []*Zone        `json:"zones"`
+	Messages  []*Message     `json:"messages,omitempty"` // Messages sent this round, only in the observer stream
+	Debug     [][]DebugShape `json:"debug,omitempty"`    // Debug shapes sent by each player this round, only in the observer stream
+	runner    *client.Runner `json:"-"`
+	Round     int            `json:"round"`
+	perlin    *perlin.Perlin `json:"-"`
+	Config    GameConfig     `json:"-"`
 
-func NewMap() *Map {
-	m := &Map{Radius: Radius}
-	m.perlin = perlin.NewPerlin(2, 2, 3, rand.Int63())
+	nextEventID int
+	nextMineID  int
+	spawns      []Position
+	inbox       []*Message // Messages sent last round, delivered to the players this round
+}
 
-	for range AsteroidCount {
-		NewAsteroid(m)
+func NewMap(config GameConfig, generator MapGenerator, players int) *Map {
+	if config.Seed != 0 {
+		SeedRandom(config.Seed)
 	}
 
-	for range WormholeCount {
-		NewWormholes(m)
+	m := &Map{Radius: Radius, Config: config, Events: []*Event{}, Mines: []*Mine{}, Zones: []*Zone{}, Messages: []*Message{}}
+	m.perlin = perlin.NewPerlin(2, 2, 3, rng.Int63())
+	m.spawns = generator.Generate(m, players)
+
+	for range config.Zones {
+		NewZone(m)
 	}
 
 	return m
 }
 
+// SpawnPosition returns the position prepared by the map generator for the player's mothership
+func (m *Map) SpawnPosition(playerID int) Position {
+	if playerID < len(m.spawns) {
+		return m.spawns[playerID]
+	}
+	return RandomPosition(m)
+}
+
 func (m *Map) ShouldContinue() bool {
+	if m.Config.Elimination && len(Teams(m)) > 1 && RemainingTeams(m) <= 1 {
+		return false
+	}
 	return m.Round <= 2025
 }
 
 func (m *Map) Tick() {
 	UpdateAsteroidPositions(m)
+	UpdateZones(m)
 	UpdateScores(m)
 	m.Round++
+	ScheduleEvents(m)
 }
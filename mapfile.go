@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// MapFile is a hand-authored map loaded with the `map` game argument. It also serves
+// as the format of maps exported from running or replayed games.
+type MapFile struct {
+	Radius    float64       `json:"radius,omitempty"` // 0 means the default map radius
+	Asteroids []MapAsteroid `json:"asteroids"`
+	Wormholes []MapWormhole `json:"wormholes"`
+	Spawns    []Position    `json:"spawns"`          // Mothership positions, players without a spawn start at a random position
+	Ships     []MapShip     `json:"ships,omitempty"` // Ships given to players at the start of the game
+}
+
+type MapAsteroid struct {
+	Position Position     `json:"position"`
+	Type     AsteroidType `json:"type"`
+	Size     float64      `json:"size"`
+}
+
+type MapWormhole struct {
+	A Position `json:"a"`
+	B Position `json:"b"`
+}
+
+type MapShip struct {
+	PlayerID int      `json:"player"`
+	Type     ShipType `json:"type"`
+	Position Position `json:"position"`
+	Health   int      `json:"health,omitempty"` // 0 means full health
+	Fuel     float64  `json:"fuel,omitempty"`   // 0 means ShipStartFuel
+	Rock     int      `json:"rock,omitempty"`
+}
+
+func LoadMapFile(path string) (*MapFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &MapFile{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("invalid map file %v: %w", path, err)
+	}
+
+	for i, asteroid := range f.Asteroids {
+		if asteroid.Type != RockAsteroid && asteroid.Type != FuelAsteroid {
+			return nil, fmt.Errorf("invalid map file %v: asteroid %d has unknown type %d", path, i, asteroid.Type)
+		}
+		if asteroid.Size <= 0 {
+			return nil, fmt.Errorf("invalid map file %v: asteroid %d has non-positive size", path, i)
+		}
+	}
+	for i, ship := range f.Ships {
+		if ship.Type <= MotherShip || ship.Type > ScoutShip {
+			return nil, fmt.Errorf("invalid map file %v: ship %d has invalid type %d", path, i, ship.Type)
+		}
+	}
+
+	return f, nil
+}
+
+func (f *MapFile) Generate(m *Map, players int) []Position {
+	if f.Radius > 0 {
+		m.Radius = f.Radius
+	}
+
+	for _, asteroid := range f.Asteroids {
+		a := NewAsteroidAt(m, asteroid.Position)
+		a.Type = asteroid.Type
+		a.Size = asteroid.Size
+	}
+
+	for _, wormhole := range f.Wormholes {
+		NewWormholesAt(m, wormhole.A, wormhole.B)
+	}
+
+	return f.Spawns
+}
+
+// GenerateShips gives the preset ships to the players, ships of missing players are skipped
+func (f *MapFile) GenerateShips(m *Map) {
+	for _, preset := range f.Ships {
+		if preset.PlayerID < 0 || preset.PlayerID >= len(m.Players) {
+			continue
+		}
+
+		ship := NewShip(m, m.Players[preset.PlayerID], preset.Type)
+		ship.Position = preset.Position
+		ship.Rock = preset.Rock
+		if preset.Health > 0 {
+			ship.Health = min(preset.Health, ShipHealth(preset.Type))
+		}
+		if preset.Fuel > 0 {
+			ship.Fuel = preset.Fuel
+		}
+	}
+}
+
+// ExportMapFile describes the current state of the map in the map file format
+func ExportMapFile(m *Map) *MapFile {
+	f := &MapFile{
+		Radius:    m.Radius,
+		Asteroids: []MapAsteroid{},
+		Wormholes: []MapWormhole{},
+		Spawns:    []Position{},
+	}
+
+	for _, asteroid := range m.Asteroids {
+		if asteroid == nil {
+			continue
+		}
+		f.Asteroids = append(f.Asteroids, MapAsteroid{asteroid.Position, asteroid.Type, asteroid.Size})
+	}
+
+	for _, wormhole := range m.Wormholes {
+		if wormhole.ID < wormhole.TargetID {
+			f.Wormholes = append(f.Wormholes, MapWormhole{wormhole.Position, m.Wormholes[wormhole.TargetID].Position})
+		}
+	}
+
+	for _, p := range m.Players {
+		f.Spawns = append(f.Spawns, p.MotherShip.Position)
+	}
+
+	for _, ship := range m.Ships {
+		if ship == nil || ship.IsDestroyed || ship.Type == MotherShip {
+			continue
+		}
+		f.Ships = append(f.Ships, MapShip{
+			PlayerID: ship.PlayerID,
+			Type:     ship.Type,
+			Position: ship.Position,
+			Health:   ship.Health,
+			Fuel:     ship.Fuel,
+			Rock:     ship.Rock,
+		})
+	}
+
+	return f
+}
+
+func WriteMapFile(m *Map, path string) error {
+	data, err := json.MarshalIndent(ExportMapFile(m), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ReadObserverState returns the first map from an observer log (plain or gzipped) recorded
+// in the given round or later. Negative round selects the last recorded round.
+func ReadObserverState(path string, round int) (*Map, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var found *Map
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1<<30)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		m := &Map{}
+		if err := json.Unmarshal([]byte(line), m); err != nil {
+			return nil, fmt.Errorf("invalid observer state: %w", err)
+		}
+		found = m
+		if round >= 0 && m.Round >= round {
+			return m, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if found == nil || round >= 0 {
+		return nil, fmt.Errorf("round %d not found in %v", round, path)
+	}
+	return found, nil
+}
+
+// ExportMapCommand implements `server export-map <observer file> [round]`, printing the map to stdout
+func ExportMapCommand(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: export-map <observer file> [round]")
+	}
+
+	round := 0
+	if len(args) == 2 {
+		if _, err := fmt.Sscan(args[1], &round); err != nil {
+			return fmt.Errorf("invalid round '%v'", args[1])
+		}
+	}
+
+	m, err := ReadObserverState(args[0], round)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(ExportMapFile(m), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(data))
+	return err
+}
@@ -0,0 +1,274 @@
+package main
+
+import "math"
+
+// MapGenerator fills a new map with asteroids and wormholes and picks spawn positions
+// of the motherships. It is selected with the `generator` game argument.
+type MapGenerator interface {
+	Generate(m *Map, players int) []Position
+}
+
+var mapGenerators = map[string]MapGenerator{
+	"random":    RandomGenerator{},
+	"symmetric": SymmetricGenerator{},
+	"clustered": ClusteredGenerator{},
+}
+
+// ShipGenerator is implemented by generators which also give ships to the players once they join
+type ShipGenerator interface {
+	GenerateShips(m *Map)
+}
+
+// MapGenerator returns the generator selected by the game arguments, a map file takes precedence
+func (c GameConfig) MapGenerator() (MapGenerator, error) {
+	if c.MapFile != "" {
+		return LoadMapFile(c.MapFile)
+	}
+	if generator, ok := mapGenerators[c.Generator]; ok {
+		return generator, nil
+	}
+	return RandomGenerator{}, nil
+}
+
+// RandomGenerator scatters asteroids and wormholes uniformly over the map. Spawns are kept
+// at least MinMothershipDistance apart and every spawn gets topped up to the same number
+// of rock and fuel asteroids in its neighbourhood.
+type RandomGenerator struct{}
+
+func (RandomGenerator) Generate(m *Map, players int) []Position {
+	for range AsteroidCount {
+		NewAsteroid(m)
+	}
+
+	for range WormholeCount {
+		NewWormholes(m)
+	}
+
+	spawns := make([]Position, 0, players)
+	for range players {
+		spawns = append(spawns, randomSpawnPosition(m, spawns))
+	}
+
+	BalanceSpawnResources(m, spawns)
+	return spawns
+}
+
+// randomSpawnPosition returns a random position far enough from the other spawns. If no
+// such position is found, the one furthest from the other spawns is used.
+func randomSpawnPosition(m *Map, spawns []Position) Position {
+	limit := m.Radius - SpawnResourceRadius
+
+	var best Position
+	bestDistance := -1.0
+	for range SpawnPlacementAttempts {
+		candidate := Position{RandomFloat(-limit, limit), RandomFloat(-limit, limit)}
+
+		distance := math.Inf(1)
+		for _, spawn := range spawns {
+			distance = min(distance, candidate.Distance(spawn))
+		}
+
+		if distance >= MinMothershipDistance {
+			return candidate
+		}
+		if distance > bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// BalanceSpawnResources adds asteroids around spawns so that every spawn has the same
+// number of rock and fuel asteroids within SpawnResourceRadius
+func BalanceSpawnResources(m *Map, spawns []Position) {
+	counts := make([]map[AsteroidType]int, len(spawns))
+	target := map[AsteroidType]int{}
+	for i, spawn := range spawns {
+		counts[i] = CountAsteroidsAround(m, spawn, SpawnResourceRadius)
+		for _, asteroidType := range []AsteroidType{RockAsteroid, FuelAsteroid} {
+			target[asteroidType] = max(target[asteroidType], counts[i][asteroidType])
+		}
+	}
+
+	for i, spawn := range spawns {
+		for _, asteroidType := range []AsteroidType{RockAsteroid, FuelAsteroid} {
+			for range target[asteroidType] - counts[i][asteroidType] {
+				a := NewAsteroidAt(m, RandomOffsetPosition(spawn, SpawnResourceRadius))
+				a.Type = asteroidType
+			}
+		}
+	}
+}
+
+func CountAsteroidsAround(m *Map, position Position, radius float64) map[AsteroidType]int {
+	counts := map[AsteroidType]int{RockAsteroid: 0, FuelAsteroid: 0}
+	for _, asteroid := range m.Asteroids {
+		if asteroid != nil && asteroid.Position.Distance(position) <= radius {
+			counts[asteroid.Type]++
+		}
+	}
+	return counts
+}
+
+// SymmetricGenerator generates one sector of the map and copies it rotated around
+// the center for every player, so each player starts in an identical neighbourhood
+type SymmetricGenerator struct{}
+
+func (SymmetricGenerator) Generate(m *Map, players int) []Position {
+	players = max(players, 1)
+	sector := 2 * math.Pi / float64(players)
+
+	for range AsteroidCount / players {
+		position := RandomPositionInSector(m.Radius, 0, sector)
+		original := NewAsteroidAt(m, position)
+		for k := 1; k < players; k++ {
+			a := NewAsteroidAt(m, position.Rotate(sector*float64(k)))
+			a.Type = original.Type
+			a.Size = original.Size
+		}
+	}
+
+	for range WormholeCount / players {
+		from := RandomPositionInSector(m.Radius, 0, sector)
+		to := RandomPositionInSector(m.Radius, 0, 2*math.Pi)
+		for k := range players {
+			angle := sector * float64(k)
+			NewWormholesAt(m, from.Rotate(angle), to.Rotate(angle))
+		}
+	}
+
+	// Move the spawns further from the center if there are too many players to keep them apart
+	distance := float64(SymmetricSpawnDistance)
+	if players > 1 {
+		distance = min(m.Radius, max(distance, MinMothershipDistance/(2*math.Sin(sector/2))))
+	}
+
+	spawns := make([]Position, 0, players)
+	for k := range players {
+		spawn := Position{distance, 0}.Rotate(sector * (float64(k) + 0.5))
+		spawns = append(spawns, spawn)
+	}
+	return spawns
+}
+
+// RegionConfig describes one kind of region generated by the clustered generator
+type RegionConfig struct {
+	Regions   int     `json:"regions"`    // Number of regions of this kind
+	Asteroids int     `json:"asteroids"`  // Number of asteroids generated in every region
+	FuelRatio float64 `json:"fuel_ratio"` // Chance that a generated asteroid is a fuel asteroid
+	MinSize   float64 `json:"min_size"`
+	MaxSize   float64 `json:"max_size"`
+	MinRadius float64 `json:"min_radius"` // Size of the region, belts are twice as long as their radius
+	MaxRadius float64 `json:"max_radius"`
+	Weight    float64 `json:"weight"` // Score weight of the generated asteroids
+}
+
+// RegionKinds lists the regions of the clustered generator in the order they are generated
+var RegionKinds = []string{"void", "belt", "nebula", "giant", "scatter"}
+
+var DefaultRegions = map[string]RegionConfig{
+	"void":    {Regions: 3, MinRadius: 2000, MaxRadius: 3500},
+	"belt":    {Regions: 6, Asteroids: 50, FuelRatio: 0.1, MinSize: MinAsteroidSize, MaxSize: MaxAsteroidSize, MinRadius: 1500, MaxRadius: 3000, Weight: 1},
+	"nebula":  {Regions: 4, Asteroids: 15, FuelRatio: 0.9, MinSize: 30, MaxSize: 45, MinRadius: 1500, MaxRadius: 2500, Weight: 1},
+	"giant":   {Regions: 4, Asteroids: 1, FuelRatio: 0.5, MinSize: 80, MaxSize: 100, Weight: 3},
+	"scatter": {Regions: 1, Asteroids: 120, FuelRatio: 0.5, MinSize: MinAsteroidSize, MaxSize: MaxAsteroidSize, Weight: 1},
+}
+
+// Region returns the configuration of the region kind, game arguments override the defaults
+func (c GameConfig) Region(kind string) RegionConfig {
+	if region, ok := c.Regions[kind]; ok {
+		return region
+	}
+	return DefaultRegions[kind]
+}
+
+type void struct {
+	Position Position
+	Radius   float64
+}
+
+// ClusteredGenerator groups asteroids into dense rock belts, sparse fuel nebulae and
+// a few giant asteroids, leaving empty voids between them
+type ClusteredGenerator struct{}
+
+func (ClusteredGenerator) Generate(m *Map, players int) []Position {
+	var voids []void
+	for _, kind := range RegionKinds {
+		region := m.Config.Region(kind)
+		for range region.Regions {
+			radius := RandomFloat(region.MinRadius, region.MaxRadius)
+			center := clusterPosition(m, voids, func() Position { return RandomPosition(m) })
+
+			switch kind {
+			case "void":
+				voids = append(voids, void{center, radius})
+			case "belt":
+				direction := RandomOffsetPosition(Position{}, 1).Normalize()
+				for range region.Asteroids {
+					newClusterAsteroid(m, voids, region, func() Position {
+						along := direction.Scale(RandomFloat(-radius, radius))
+						return RandomOffsetPosition(center.Add(along), BeltWidth)
+					})
+				}
+			case "nebula":
+				for range region.Asteroids {
+					newClusterAsteroid(m, voids, region, func() Position { return RandomOffsetPosition(center, radius) })
+				}
+			case "giant":
+				for range region.Asteroids {
+					newClusterAsteroid(m, voids, region, func() Position { return center })
+				}
+			case "scatter":
+				for range region.Asteroids {
+					newClusterAsteroid(m, voids, region, func() Position { return RandomPosition(m) })
+				}
+			}
+		}
+	}
+
+	for range WormholeCount {
+		NewWormholes(m)
+	}
+
+	spawns := make([]Position, 0, players)
+	for range players {
+		spawns = append(spawns, randomSpawnPosition(m, spawns))
+	}
+
+	BalanceSpawnResources(m, spawns)
+	return spawns
+}
+
+// clusterPosition draws positions until one lies on the map outside of all voids
+func clusterPosition(m *Map, voids []void, position func() Position) Position {
+	var candidate Position
+	for range RegionPlacementAttempts {
+		candidate = position()
+		if math.Abs(candidate.X) > m.Radius || math.Abs(candidate.Y) > m.Radius {
+			continue
+		}
+
+		inVoid := false
+		for _, v := range voids {
+			if candidate.Distance(v.Position) <= v.Radius {
+				inVoid = true
+				break
+			}
+		}
+		if !inVoid {
+			return candidate
+		}
+	}
+	return candidate
+}
+
+func newClusterAsteroid(m *Map, voids []void, region RegionConfig, position func() Position) *Asteroid {
+	a := NewAsteroidAt(m, clusterPosition(m, voids, position))
+	a.Type = RockAsteroid
+	if rng.Float64() < region.FuelRatio {
+		a.Type = FuelAsteroid
+	}
+	a.Size = RandomFloat(region.MinSize, region.MaxSize)
+	a.Weight = region.Weight
+	return a
+}
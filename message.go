@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Message is sent by a bot to another player or to everybody. It is delivered
+// in the recipients' game state in the next round.
+type Message struct {
+	Round   int             `json:"round"`   // Round in which the message was sent
+	From    int             `json:"from"`    // ID of the sending player
+	To      int             `json:"to"`      // ID of the receiving player, -1 for a broadcast
+	Payload json.RawMessage `json:"payload"` // Any JSON value, usually a string
+}
+
+// MessagesFor returns the messages delivered to the player this round
+func MessagesFor(m *Map, p *Player) []*Message {
+	messages := []*Message{}
+	for _, message := range m.inbox {
+		if message.From != p.ID && (message.To == -1 || message.To == p.ID) {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// DeliverMessages moves the messages sent last round to the inbox, they are sent to the players this round
+func DeliverMessages(m *Map) {
+	m.inbox = m.Messages
+	m.Messages = []*Message{}
+}
+
+// MessageTurnData sends the payload to the player with ID `to`, or to all players if `to` is missing
+type MessageTurnData struct {
+	To      *int            `json:"to,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func (t MessageTurnData) Execute(m *Map, p *Player) error {
+	to := -1
+	if t.To != nil {
+		to = *t.To
+		if to < 0 || to >= len(m.Players) || to == p.ID {
+			return fmt.Errorf("invalid recipient player id: %v", to)
+		}
+	}
+
+	if len(t.Payload) == 0 || !json.Valid(t.Payload) {
+		return fmt.Errorf("message payload must be a JSON value")
+	}
+	if len(t.Payload) > MessageMaxSize {
+		return fmt.Errorf("message payload too long: %v > %v bytes", len(t.Payload), MessageMaxSize)
+	}
+
+	sent := 0
+	for _, message := range m.Messages {
+		if message.From == p.ID {
+			sent++
+		}
+	}
+	if sent >= PlayerMaxMessages {
+		return fmt.Errorf("too many messages this round: %v", PlayerMaxMessages)
+	}
+
+	m.Messages = append(m.Messages, &Message{Round: m.Round, From: p.ID, To: to, Payload: t.Payload})
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import "fmt"
+
+type Mine struct {
+	ID       int      `json:"id"`
+	PlayerID int      `json:"player"`
+	Position Position `json:"position"`
+}
+
+func NewMine(m *Map, ship *Ship) *Mine {
+	mine := &Mine{
+		ID:       m.nextMineID,
+		PlayerID: ship.PlayerID,
+		Position: ship.Position,
+	}
+
+	m.Mines = append(m.Mines, mine)
+	m.nextMineID++
+	return mine
+}
+
+// MineVisibleTo reports whether the player can see the mine. Mines are always visible
+// to their owner and allies, enemies only see them from close range.
+func MineVisibleTo(m *Map, mine *Mine, p *Player) bool {
+	if m.Allied(mine.PlayerID, p.ID) {
+		return true
+	}
+
+	for _, ship := range m.Ships {
+		if ship == nil || ship.IsDestroyed || ship.PlayerID != p.ID {
+			continue
+		}
+
+		distance := MineVisibilityDistance
+		if ship.Type == ScoutShip {
+			distance = ScoutSensorRange
+		}
+		if ship.Position.Distance(mine.Position) <= float64(distance) {
+			return true
+		}
+	}
+	return false
+}
+
+// mineProof reports whether mines can't hurt the ship: motherships, and ships protected
+// near their mothership like they are from shots
+func mineProof(m *Map, ship *Ship) bool {
+	if ship.Type == MotherShip {
+		return true
+	}
+	mothership := m.Players[ship.PlayerID].MotherShip
+	return ship.Position.Distance(mothership.Position) <= ShipRepairDistance
+}
+
+// TriggerMines detonates every mine with an enemy ship within its trigger radius.
+// Detonated mines are removed from the map.
+func TriggerMines(m *Map) {
+	remaining := []*Mine{}
+	for _, mine := range m.Mines {
+		detonated := false
+		for _, ship := range m.Ships {
+			if ship == nil || ship.IsDestroyed || mineProof(m, ship) || m.Allied(ship.PlayerID, mine.PlayerID) {
+				continue
+			}
+			if ship.Position.Distance(mine.Position) <= MineTriggerDistance {
+				DetonateMine(m, mine)
+				detonated = true
+				break
+			}
+		}
+		if !detonated {
+			remaining = append(remaining, mine)
+		}
+	}
+	m.Mines = remaining
+}
+
+// DetonateMine deals area damage to all ships around the mine, including its owner's
+func DetonateMine(m *Map, mine *Mine) {
+	m.runner.Log(fmt.Sprintf("Mine %d (player %d) detonated", mine.ID, mine.PlayerID))
+
+	for _, ship := range m.Ships {
+		if ship == nil || ship.IsDestroyed || mineProof(m, ship) {
+			continue
+		}
+		if ship.Position.Distance(mine.Position) > MineBlastRadius {
+			continue
+		}
+
+		ship.Health -= MineDamage
+		if ship.Health <= 0 {
+			DestroyShip(m, ship)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import "fmt"
+
+// Navigation is an autopilot order. Every round the server steers the ship towards
+// the target and brakes to the arrival speed once it gets there.
+type Navigation struct {
+	Target       Position `json:"target"`         // Current target position, follows the target entity
+	AsteroidID   int      `json:"asteroid_id"`    // Followed asteroid, -1 if none
+	ShipID       int      `json:"target_ship_id"` // Followed friendly ship, -1 if none
+	Speed        float64  `json:"speed"`          // Cruise speed
+	ArrivalSpeed float64  `json:"arrival_speed"`  // Speed kept in the direction of travel after arrival
+}
+
+// UpdateTarget moves the target to the followed entity. It returns an error if the entity is gone.
+func (n *Navigation) UpdateTarget(m *Map, p *Player) error {
+	if n.AsteroidID != -1 {
+		asteroid := m.Asteroids[n.AsteroidID]
+		if asteroid == nil {
+			return fmt.Errorf("target asteroid %v no longer exists", n.AsteroidID)
+		}
+		n.Target = asteroid.Position
+	}
+
+	if n.ShipID != -1 {
+		ship := m.Ships[n.ShipID]
+		if ship.IsDestroyed || !m.Allied(ship.PlayerID, p.ID) {
+			return fmt.Errorf("target ship %v is no longer friendly", n.ShipID)
+		}
+		n.Target = ship.Position
+	}
+
+	return nil
+}
+
+// StepNavigation steers all navigating ships of the player, paying fuel as for a MoveTurn
+func StepNavigation(m *Map, p *Player) {
+	for _, ship := range m.Ships {
+		if ship == nil || ship.PlayerID != p.ID || ship.IsDestroyed || ship.Navigation == nil || ship.Cloaked {
+			continue
+		}
+
+		if err := SteerShip(m, p, ship); err != nil {
+			m.runner.Log(fmt.Sprintf("navigation of ship %d cancelled: %v", ship.ID, err))
+			ship.Navigation = nil
+			if ship.Order != nil {
+				EndOrder(m, p, ship, err.Error())
+			}
+		}
+	}
+}
+
+func SteerShip(m *Map, p *Player, ship *Ship) error {
+	n := ship.Navigation
+	if err := n.UpdateTarget(m, p); err != nil {
+		return err
+	}
+
+	offset := n.Target.Sub(ship.Position)
+	distance := offset.Size()
+
+	var desired Position
+	if distance <= NavigationArrivalDistance {
+		desired = ship.Vector.Normalize().Scale(n.ArrivalSpeed)
+		if n.AsteroidID == -1 && n.ShipID == -1 {
+			ship.Navigation = nil
+		}
+	} else {
+		// Approach at cruise speed and slow down to land exactly on the target
+		desired = offset.Normalize().Scale(min(n.Speed, distance))
+	}
+
+	delta := desired.Sub(ship.Vector)
+	if delta.Size() < NavigationTolerance {
+		return nil
+	}
+	return applyMovement(m, p, ship, delta)
+}
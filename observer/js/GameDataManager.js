@@ -8,6 +8,20 @@ class GameDataManager {
         this.MATERIAL_TO_SURFACE_RATIO = 1.0;
         // Dynamic player elements registry
         this.playerElementsRegistry = new Map(); // playerId -> elements map
+        // Players whose debug shapes are hidden
+        this.hiddenDebug = new Set();
+    }
+
+    isDebugVisible(playerId) {
+        return !this.hiddenDebug.has(playerId);
+    }
+
+    setDebugVisible(playerId, visible) {
+        if (visible) {
+            this.hiddenDebug.delete(playerId);
+        } else {
+            this.hiddenDebug.add(playerId);
+        }
     }
 
     async loadGameData() {
@@ -160,7 +174,8 @@ class GameDataManager {
 
             // Update player header with name and ID
             if (playerElements.header) {
-                playerElements.header.textContent = `${player.name} (${player.id})`;
+                const eliminated = player.eliminated_round >= 0 ? ` - eliminated in round ${player.eliminated_round}` : '';
+                playerElements.header.textContent = `${player.name} (${player.id})${eliminated}`;
             }
 
             // Update score
@@ -181,6 +196,18 @@ class GameDataManager {
             if (playerElements.ships) {
                 playerElements.ships.textContent = playerShips.length;
             }
+
+            // Messages sent by the player this round, payloads come from bots so they are only used as text
+            if (playerElements.messages) {
+                playerElements.messages.replaceChildren();
+                (currentGameData.messages || []).filter(m => m.from === player.id).forEach(message => {
+                    const line = document.createElement('div');
+                    const recipient = message.to === -1 ? 'all' : message.to;
+                    const payload = typeof message.payload === 'string' ? message.payload : JSON.stringify(message.payload);
+                    line.textContent = `→ ${recipient}: ${payload.length > 80 ? payload.slice(0, 80) + '…' : payload}`;
+                    playerElements.messages.appendChild(line);
+                });
+            }
         });
     }
 
@@ -277,6 +304,27 @@ class GameDataManager {
                 html += `<span class="entity-detail">Fuel: ${data.fuel}</span>`;
                 html += `<span class="entity-detail">Type: ${this.getShipTypeName(data.type)}</span>`;
                 html += `<span class="entity-detail">Rock: ${data.rock}</span>`;
+                if (data.action_points !== undefined) {
+                    html += `<span class="entity-detail">Action points: ${data.action_points}</span>`;
+                }
+                if (data.type === 5) {
+                    html += `<span class="entity-detail">Ammo: ${data.ammo}</span>`;
+                    html += `<span class="entity-detail">Heat: ${data.heat}</span>`;
+                    html += `<span class="entity-detail">Cooldown: ${data.cooldown}</span>`;
+                }
+                if (data.boarding_id !== undefined && data.boarding_id !== -1) {
+                    html += `<span class="entity-detail">Boarding: ${data.boarding_id} (${data.boarding_progress})</span>`;
+                }
+                if (data.cloaked) {
+                    html += `<span class="entity-detail">Cloaked</span>`;
+                }
+                if (data.navigation) {
+                    html += `<span class="entity-detail">Autopilot: (${Math.round(data.navigation.target.x)}, ${Math.round(data.navigation.target.y)})</span>`;
+                }
+                if (data.order) {
+                    const target = data.order.type === 'mine' ? `asteroid ${data.order.asteroid_id}` : `ship ${data.order.source_id}`;
+                    html += `<span class="entity-detail">Order: ${data.order.type} ${target} → ship ${data.order.destination_id}${data.order.unloading ? ' (unloading)' : ''}</span>`;
+                }
                 break;
             case 'asteroid':
                 html += `<span class="entity-detail">Pos: (${Math.round(data.position.x)}, ${Math.round(data.position.y)})</span>`;
@@ -291,6 +339,12 @@ class GameDataManager {
                 if (data.surface !== undefined) {
                     html += `<span class="entity-detail">Surface: ${data.surface}</span>`;
                 }
+                if (data.is_wreck) {
+                    html += `<span class="entity-detail">Wreck</span>`;
+                }
+                if (data.damage) {
+                    html += `<span class="entity-detail">Damage: ${data.damage}</span>`;
+                }
                 break;
             case 'wormhole':
                 html += `<span class="entity-detail">Pos: (${Math.round(data.position.x)}, ${Math.round(data.position.y)})</span>`;
@@ -333,7 +387,8 @@ class GameDataManager {
             2: "DrillShip",
             3: "TankerShip",
             4: "TruckShip",
-            5: "BattleShip"
+            5: "BattleShip",
+            6: "ScoutShip"
         };
         return shipTypes[shipType] || `Unknown (${shipType})`;
     }
@@ -392,7 +447,15 @@ class GameDataManager {
                 <span>Ships:</span>
                 <span class="player-ships">0</span>
             </div>
+            <div class="stat-row">
+                <label for="player${player.id}Debug">Debug:</label>
+                <input type="checkbox" class="player-debug" id="player${player.id}Debug" ${this.isDebugVisible(player.id) ? 'checked' : ''}>
+            </div>
+            <div class="player-messages"></div>
         `;
+        panel.querySelector('.player-debug').addEventListener('change', event => {
+            this.setDebugVisible(player.id, event.target.checked);
+        });
 
         return panel;
     }
@@ -404,7 +467,8 @@ class GameDataManager {
             score: panel.querySelector('.player-score'),
             rock: panel.querySelector('.player-rock'),
             fuel: panel.querySelector('.player-fuel'),
-            ships: panel.querySelector('.player-ships')
+            ships: panel.querySelector('.player-ships'),
+            messages: panel.querySelector('.player-messages')
         };
         this.playerElementsRegistry.set(playerId, elements);
     }
@@ -126,9 +126,15 @@ class Renderer {
 
 
         this.renderBoundary();
+        this.renderEvents();
+        this.renderZones();
         this.renderWormholes();
         this.renderAsteroids();
+        this.renderMines();
+        this.renderTowBeams();
+        this.renderNavigation();
         this.renderShips();
+        this.renderDebug();
 
         if (this.selectedEntity) {
             this.renderSelection();
@@ -189,6 +195,99 @@ class Renderer {
         this.ctx.stroke();
     }
 
+    renderEvents() {
+        if (!this.gameData.events) return;
+
+        const eventStyles = {
+            0: { fill: 'rgba(255, 170, 0, 0.15)', stroke: 'rgba(255, 170, 0, 0.8)', label: 'Solar storm' },
+            1: { fill: 'rgba(200, 80, 40, 0.15)', stroke: 'rgba(200, 80, 40, 0.8)', label: 'Meteor shower' },
+            2: { fill: 'rgba(150, 100, 255, 0.15)', stroke: 'rgba(150, 100, 255, 0.8)', label: 'Ion cloud' }
+        };
+
+        this.gameData.events.forEach(event => {
+            const style = eventStyles[event.type];
+            if (!style) return;
+
+            // Upcoming events are only outlined, active ones are filled
+            const active = this.gameData.round >= event.start_round && this.gameData.round <= event.end_round;
+            const label = active ? style.label : `${style.label} in ${event.start_round - this.gameData.round}`;
+
+            this.ctx.save();
+            this.ctx.strokeStyle = style.stroke;
+            this.ctx.fillStyle = style.fill;
+            this.ctx.lineWidth = 2;
+            if (!active) {
+                this.ctx.setLineDash([10, 10]);
+            }
+
+            let labelPos;
+            if (event.radius === 0) {
+                // Map-wide event covers the whole boundary square
+                const center = this.camera.worldToScreen(0, 0);
+                const radius = this.gameData.radius * this.camera.zoom;
+                this.ctx.beginPath();
+                this.ctx.rect(center.x - radius, center.y - radius, radius * 2, radius * 2);
+                labelPos = { x: center.x, y: center.y - radius + 20 };
+            } else {
+                const pos = this.camera.worldToScreen(event.position.x, event.position.y);
+                const radius = event.radius * this.camera.zoom;
+                this.ctx.beginPath();
+                this.ctx.arc(pos.x, pos.y, radius, 0, Math.PI * 2);
+                labelPos = pos;
+            }
+            if (active) {
+                this.ctx.fill();
+            }
+            this.ctx.stroke();
+
+            this.ctx.fillStyle = style.stroke;
+            this.ctx.font = '14px Arial';
+            this.ctx.textAlign = 'center';
+            this.ctx.fillText(label, labelPos.x, labelPos.y);
+            this.ctx.restore();
+        });
+    }
+
+    renderZones() {
+        if (!this.gameData.zones) return;
+
+        this.gameData.zones.forEach(zone => {
+            if (!zone || zone.position === undefined) return;
+
+            const pos = this.camera.worldToScreen(zone.position.x, zone.position.y);
+            const radius = zone.radius * this.camera.zoom;
+
+            // Held zones are filled with the owner's color, contested ones flash red
+            let color = 'rgba(255, 255, 255, 0.6)';
+            let label = `Zone ${zone.id}`;
+            if (zone.contested) {
+                color = '#ff4a4a';
+                label += ' (contested)';
+            } else if (zone.owner_id !== -1) {
+                color = this.dataManager.getPlayerColor(zone.owner_id);
+            }
+
+            this.ctx.save();
+            this.ctx.strokeStyle = color;
+            this.ctx.fillStyle = color;
+            this.ctx.lineWidth = 3;
+            this.ctx.setLineDash([20, 10]);
+            this.ctx.beginPath();
+            this.ctx.arc(pos.x, pos.y, radius, 0, Math.PI * 2);
+            this.ctx.stroke();
+            if (zone.owner_id !== -1 || zone.contested) {
+                this.ctx.globalAlpha = 0.1;
+                this.ctx.fill();
+                this.ctx.globalAlpha = 1;
+            }
+
+            this.ctx.font = '14px Arial';
+            this.ctx.textAlign = 'center';
+            this.ctx.fillText(label, pos.x, pos.y - radius - 8);
+            this.ctx.restore();
+        });
+    }
+
     renderWormholes() {
         this.gameData.wormholes.forEach(wormhole => {
             if (!wormhole || wormhole.position === undefined || wormhole.id === undefined) {
@@ -293,6 +392,139 @@ class Renderer {
             this.ctx.beginPath();
             this.ctx.arc(pos.x + radius * 0.2, pos.y + radius * 0.2, radius * 0.8, 0, Math.PI * 2);
             this.ctx.fill();
+
+            // Rusty outline marks salvageable wreckage
+            if (asteroid.is_wreck) {
+                this.ctx.strokeStyle = '#b7410e';
+                this.ctx.lineWidth = 2;
+                this.ctx.setLineDash([4, 4]);
+                this.ctx.beginPath();
+                this.ctx.arc(pos.x, pos.y, radius, 0, Math.PI * 2);
+                this.ctx.stroke();
+                this.ctx.setLineDash([]);
+            }
+        });
+    }
+
+    renderMines() {
+        if (!this.gameData.mines) return;
+
+        this.gameData.mines.forEach(mine => {
+            if (!mine || mine.position === undefined) return;
+
+            const pos = this.camera.worldToScreen(mine.position.x, mine.position.y);
+            const radius = 8 * this.camera.zoom;
+
+            // Spiky ball in the owner's color
+            this.ctx.fillStyle = this.dataManager.getPlayerColor(mine.player);
+            this.ctx.strokeStyle = '#ff4a4a';
+            this.ctx.lineWidth = 2;
+            this.ctx.beginPath();
+            for (let i = 0; i < 8; i++) {
+                const angle = (Math.PI / 4) * i;
+                this.ctx.moveTo(pos.x + Math.cos(angle) * radius, pos.y + Math.sin(angle) * radius);
+                this.ctx.lineTo(pos.x + Math.cos(angle) * radius * 1.6, pos.y + Math.sin(angle) * radius * 1.6);
+            }
+            this.ctx.stroke();
+            this.ctx.beginPath();
+            this.ctx.arc(pos.x, pos.y, radius, 0, Math.PI * 2);
+            this.ctx.fill();
+        });
+    }
+
+    renderNavigation() {
+        this.gameData.ships.forEach(ship => {
+            if (!ship || ship.is_destroyed || !ship.navigation) return;
+
+            const shipPos = this.camera.worldToScreen(ship.position.x, ship.position.y);
+            const targetPos = this.camera.worldToScreen(ship.navigation.target.x, ship.navigation.target.y);
+
+            // Thin dotted line to the autopilot target with a cross at the end
+            this.ctx.save();
+            this.ctx.strokeStyle = this.dataManager.getPlayerColor(ship.player);
+            this.ctx.globalAlpha = 0.5;
+            this.ctx.lineWidth = 1;
+            this.ctx.setLineDash([2, 6]);
+            this.ctx.beginPath();
+            this.ctx.moveTo(shipPos.x, shipPos.y);
+            this.ctx.lineTo(targetPos.x, targetPos.y);
+            this.ctx.stroke();
+            this.ctx.setLineDash([]);
+            this.ctx.beginPath();
+            this.ctx.moveTo(targetPos.x - 5, targetPos.y - 5);
+            this.ctx.lineTo(targetPos.x + 5, targetPos.y + 5);
+            this.ctx.moveTo(targetPos.x + 5, targetPos.y - 5);
+            this.ctx.lineTo(targetPos.x - 5, targetPos.y + 5);
+            this.ctx.stroke();
+            this.ctx.restore();
+        });
+    }
+
+    // Debug shapes sent by the bots, a point can follow a ship instead of a fixed position
+    debugPoint(position, shipId) {
+        if (shipId !== undefined && shipId !== null) {
+            const ship = this.gameData.ships[shipId];
+            if (!ship) return null;
+            return this.camera.worldToScreen(ship.position.x, ship.position.y);
+        }
+        return this.camera.worldToScreen(position.x, position.y);
+    }
+
+    renderDebug() {
+        if (!this.gameData.debug) return;
+
+        this.gameData.debug.forEach((shapes, playerId) => {
+            if (!shapes || !this.dataManager.isDebugVisible(playerId)) return;
+
+            this.ctx.save();
+            this.ctx.lineWidth = 1;
+            this.ctx.font = '12px Arial';
+            this.ctx.textAlign = 'center';
+            shapes.forEach(shape => {
+                const color = shape.color || this.dataManager.getPlayerColor(playerId);
+                this.ctx.strokeStyle = color;
+                this.ctx.fillStyle = color;
+
+                const pos = this.debugPoint(shape.position, shape.ship_id);
+                if (!pos) return;
+
+                if (shape.type === 'line') {
+                    const to = this.debugPoint(shape.to || shape.position, shape.to_ship_id);
+                    if (!to) return;
+                    this.ctx.beginPath();
+                    this.ctx.moveTo(pos.x, pos.y);
+                    this.ctx.lineTo(to.x, to.y);
+                    this.ctx.stroke();
+                } else if (shape.type === 'circle') {
+                    this.ctx.beginPath();
+                    this.ctx.arc(pos.x, pos.y, shape.radius * this.camera.zoom, 0, Math.PI * 2);
+                    this.ctx.stroke();
+                } else if (shape.type === 'text') {
+                    this.ctx.fillText(shape.text, pos.x, pos.y);
+                }
+            });
+            this.ctx.restore();
+        });
+    }
+
+    renderTowBeams() {
+        this.gameData.ships.forEach(ship => {
+            if (!ship || ship.is_destroyed || ship.towing_id === undefined || ship.towing_id === -1) return;
+
+            const asteroid = this.gameData.asteroids[ship.towing_id];
+            if (!asteroid) return;
+
+            const shipPos = this.camera.worldToScreen(ship.position.x, ship.position.y);
+            const asteroidPos = this.camera.worldToScreen(asteroid.position.x, asteroid.position.y);
+
+            this.ctx.strokeStyle = 'rgba(120, 255, 180, 0.7)';
+            this.ctx.lineWidth = 3;
+            this.ctx.setLineDash([6, 3]);
+            this.ctx.beginPath();
+            this.ctx.moveTo(shipPos.x, shipPos.y);
+            this.ctx.lineTo(asteroidPos.x, asteroidPos.y);
+            this.ctx.stroke();
+            this.ctx.setLineDash([]);
         });
     }
 
@@ -328,6 +560,9 @@ class Renderer {
             } else if (isDestroyed) {
                 this.ctx.fillStyle = this.getDestroyedColor(playerColor);
                 this.ctx.globalAlpha = 0.4; // Reduced opacity for destroyed ships
+            } else if (ship.cloaked) {
+                this.ctx.fillStyle = playerColor;
+                this.ctx.globalAlpha = 0.3; // Cloaked ships are barely visible
             } else {
                 this.ctx.fillStyle = playerColor;
                 this.ctx.globalAlpha = 1.0;
@@ -364,17 +599,18 @@ class Renderer {
             }
 
             // Add X mark for destroyed ships (but not motherships)
-            if (isDestroyed && !isMothership) {
+            // Motherships only get destroyed in elimination mode, otherwise they have 0 health
+            if (isDestroyed && (!isMothership || ship.is_destroyed)) {
                 this.drawDestroyedMark(size);
             }
 
             // Reset globalAlpha before drawing health bar to keep it fully visible
             this.ctx.globalAlpha = 1.0;
 
-            // Draw health bar or destroyed label (but not for motherships)
-            if (!isMothership) {
+            // Draw health bar or destroyed label (motherships only in elimination mode)
+            if (!isMothership || ship.health > 0 || ship.is_destroyed) {
                 if (ship.health > 0 && !isDestroyed) {
-                    const healthPercent = ship.health / 100;
+                    const healthPercent = ship.health / (isMothership ? 1000 : 100);
                     this.ctx.fillStyle = healthPercent > 0.5 ? '#4aff4a' : healthPercent > 0.25 ? '#ffff4a' : '#ff4a4a';
                     // Position healthbar above the ship in screen space, not world space
                     this.ctx.fillRect(-size, -size - 10 * this.camera.zoom, size * 2 * healthPercent, 4 * this.camera.zoom);
@@ -433,6 +669,9 @@ class Renderer {
             case 5: // BattleShip
                 this.drawBattleShip(size);
                 break;
+            case 6: // ScoutShip
+                this.drawScoutShip(size);
+                break;
             default:
                 // Default triangle for unknown types
                 this.drawDefaultShip(size);
@@ -560,6 +799,24 @@ class Renderer {
         this.ctx.stroke();
     }
 
+    drawScoutShip(size) {
+        // Slim dart shape
+        this.ctx.beginPath();
+        this.ctx.moveTo(size, 0);
+        this.ctx.lineTo(-size * 0.7, -size * 0.4);
+        this.ctx.lineTo(-size * 0.4, 0);
+        this.ctx.lineTo(-size * 0.7, size * 0.4);
+        this.ctx.closePath();
+        this.ctx.fill();
+
+        // Sensor dish
+        this.ctx.strokeStyle = '#4affd2';
+        this.ctx.lineWidth = 2;
+        this.ctx.beginPath();
+        this.ctx.arc(0, 0, size * 0.5, -Math.PI / 3, Math.PI / 3);
+        this.ctx.stroke();
+    }
+
     drawDefaultShip(size) {
         // Default triangle for unknown ship types
         this.ctx.beginPath();
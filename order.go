@@ -0,0 +1,199 @@
+package main
+
+import "fmt"
+
+type OrderType string
+
+const (
+	MineOrder  OrderType = "mine"
+	FerryOrder OrderType = "ferry"
+)
+
+type Resource string
+
+const (
+	RockResource Resource = "rock"
+	FuelResource Resource = "fuel"
+)
+
+// StandingOrder is a loop the server executes for the ship every round until it is
+// cancelled or becomes impossible. The ship flies using the autopilot and transfers
+// cargo only in rounds in which it has enough action points left.
+type StandingOrder struct {
+	Type          OrderType `json:"type"`
+	AsteroidID    int       `json:"asteroid_id"`    // Mined asteroid, -1 for ferry orders
+	SourceID      int       `json:"source_id"`      // Ship the cargo is picked up from, -1 for mine orders
+	DestinationID int       `json:"destination_id"` // Ship the cargo is unloaded to
+	Resource      Resource  `json:"resource"`
+	Amount        int       `json:"amount"`    // Cargo which triggers unloading, or is picked up on every ferry trip
+	Unloading     bool      `json:"unloading"` // The ship is carrying cargo to the destination
+}
+
+// OrderReport tells the player why the server stopped executing a standing order
+type OrderReport struct {
+	ShipID int       `json:"ship_id"`
+	Type   OrderType `json:"type"`
+	Round  int       `json:"round"`
+	Reason string    `json:"reason"`
+}
+
+// Cargo returns the amount of the resource the ship carries, fuel needed for flying is kept aside
+func Cargo(ship *Ship, resource Resource) int {
+	if resource == FuelResource {
+		return max(0, int(ship.Fuel)-OrderFuelReserve)
+	}
+	return ship.Rock
+}
+
+func transferTurn(resource Resource) TurnType {
+	if resource == FuelResource {
+		return SiphonTurn
+	}
+	return LoadTurn
+}
+
+// Transfer moves the resource between the ships using a LoadTurn or SiphonTurn
+func Transfer(m *Map, p *Player, source, destination *Ship, resource Resource, amount int) error {
+	if resource == FuelResource {
+		return SiphonTurnData{SourceID: source.ID, DestinationID: destination.ID, Amount: amount}.Execute(m, p)
+	}
+	return LoadTurnData{SourceID: source.ID, DestinationID: destination.ID, Amount: amount}.Execute(m, p)
+}
+
+// StepOrders executes the standing orders of the player's ships
+func StepOrders(m *Map, p *Player) {
+	for _, ship := range m.Ships {
+		if ship == nil || ship.PlayerID != p.ID || ship.Order == nil || ship.Cloaked {
+			continue
+		}
+
+		var err error
+		if ship.IsDestroyed {
+			err = fmt.Errorf("ship was destroyed")
+		} else if ship.Order.Type == MineOrder {
+			err = StepMineOrder(m, p, ship)
+		} else {
+			err = StepFerryOrder(m, p, ship)
+		}
+
+		if err != nil {
+			EndOrder(m, p, ship, err.Error())
+		}
+	}
+}
+
+// EndOrder stops the ship's order and reports the reason to the player
+func EndOrder(m *Map, p *Player, ship *Ship, reason string) {
+	m.runner.Log(fmt.Sprintf("order %v of ship %d ended: %v", ship.Order.Type, ship.ID, reason))
+
+	p.Reports = append(p.Reports, OrderReport{ship.ID, ship.Order.Type, m.Round, reason})
+	ship.Order = nil
+	ship.Navigation = nil
+}
+
+// orderDestination returns the order's destination ship if it can still receive cargo
+func orderDestination(m *Map, p *Player, order *StandingOrder) (*Ship, error) {
+	destination := m.Ships[order.DestinationID]
+	if destination.IsDestroyed || !m.Allied(destination.PlayerID, p.ID) {
+		return nil, fmt.Errorf("destination ship %v can no longer receive cargo", order.DestinationID)
+	}
+	return destination, nil
+}
+
+// orderNavigate points the autopilot of the ship to the asteroid or ship
+func orderNavigate(m *Map, p *Player, ship *Ship, asteroidID, shipID int) error {
+	n := ship.Navigation
+	if n == nil || n.AsteroidID != asteroidID || n.ShipID != shipID {
+		n = &Navigation{AsteroidID: asteroidID, ShipID: shipID, Speed: OrderSpeed}
+	}
+	if err := n.UpdateTarget(m, p); err != nil {
+		return err
+	}
+	ship.Navigation = n
+	return nil
+}
+
+func StepMineOrder(m *Map, p *Player, ship *Ship) error {
+	order := ship.Order
+	destination, err := orderDestination(m, p, order)
+	if err != nil {
+		return err
+	}
+	asteroid := m.Asteroids[order.AsteroidID]
+
+	if !order.Unloading {
+		if asteroid == nil && Cargo(ship, order.Resource) == 0 {
+			return fmt.Errorf("asteroid %v was depleted", order.AsteroidID)
+		}
+		if asteroid == nil || Cargo(ship, order.Resource) >= order.Amount {
+			order.Unloading = true
+		} else {
+			return orderNavigate(m, p, ship, asteroid.ID, -1)
+		}
+	}
+
+	if err := orderNavigate(m, p, ship, -1, destination.ID); err != nil {
+		return err
+	}
+	if ship.Position.Distance(destination.Position) > ShipTransferDistance || !canUseShip(ship, transferTurn(order.Resource)) {
+		return nil
+	}
+
+	if cargo := Cargo(ship, order.Resource); cargo > 0 {
+		if err := Transfer(m, p, ship, destination, order.Resource, cargo); err != nil {
+			return err
+		}
+	}
+	if asteroid == nil {
+		return fmt.Errorf("asteroid %v was depleted", order.AsteroidID)
+	}
+	order.Unloading = false
+	return nil
+}
+
+func StepFerryOrder(m *Map, p *Player, ship *Ship) error {
+	order := ship.Order
+	destination, err := orderDestination(m, p, order)
+	if err != nil {
+		return err
+	}
+	source := m.Ships[order.SourceID]
+	if source.IsDestroyed || source.PlayerID != p.ID {
+		return fmt.Errorf("source ship %v no longer belongs to player %v", order.SourceID, p.ID)
+	}
+
+	if !order.Unloading {
+		if err := orderNavigate(m, p, ship, -1, source.ID); err != nil {
+			return err
+		}
+		if ship.Position.Distance(source.Position) > ShipTransferDistance || !canUseShip(source, transferTurn(order.Resource)) {
+			return nil
+		}
+
+		// Wait at the source until it has something to carry
+		amount := min(order.Amount, Cargo(source, order.Resource))
+		if amount <= 0 {
+			return nil
+		}
+		if err := Transfer(m, p, source, ship, order.Resource, amount); err != nil {
+			return err
+		}
+		order.Unloading = true
+		return nil
+	}
+
+	if err := orderNavigate(m, p, ship, -1, destination.ID); err != nil {
+		return err
+	}
+	if ship.Position.Distance(destination.Position) > ShipTransferDistance || !canUseShip(ship, transferTurn(order.Resource)) {
+		return nil
+	}
+
+	if cargo := Cargo(ship, order.Resource); cargo > 0 {
+		if err := Transfer(m, p, ship, destination, order.Resource, cargo); err != nil {
+			return err
+		}
+	}
+	order.Unloading = false
+	return nil
+}
@@ -15,6 +15,20 @@ type Player struct {
 	MotherShip *Ship  `json:"mothership"`
 	Alive      bool   `json:"alive"`
 	Score      int    `json:"score"`
+	Team       int    `json:"team"`
+	ZoneScore  int    `json:"zone_score"` // Points collected by holding control zones
+	// Round in which the player's mothership was destroyed, -1 while it exists
+	EliminatedRound int `json:"eliminated_round"`
+	// Fleets are private, they are sent only to their owner in the game state
+	Fleets map[string]*Fleet `json:"-"`
+	// Reports of standing orders which ended since the last game state, sent only to their owner
+	Reports []OrderReport `json:"-"`
+	// Transactions rolled back in the last round, sent only to their owner
+	FailedTransactions []TransactionReport `json:"-"`
+	// Protocol extensions the bot asked for and the server supports
+	Capabilities []Capability `json:"-"`
+	// View sent in the last delta state, nil if the next state has to be full
+	deltaBase *deltaBase
 }
 
 // generateHexColor creates a deterministic hex color from a player name
@@ -96,15 +110,25 @@ func NewPlayer(m *Map, name string) *Player {
 		Name:  name,
 		Color: generateHexColor(name),
 		Alive: true,
+		Team:  -1,
+
+		EliminatedRound: -1,
+		Fleets:          map[string]*Fleet{},
 	}
 
 	s := &Ship{
-		ID:       len(m.Ships),
-		PlayerID: p.ID,
-		Position: RandomPosition(m),
-		Type:     MotherShip,
-		Rock:     PlayerStartRock,
-		Fuel:     PlayerStartFuel,
+		ID:         len(m.Ships),
+		PlayerID:   p.ID,
+		Position:   m.SpawnPosition(p.ID),
+		Type:       MotherShip,
+		Rock:       PlayerStartRock,
+		Fuel:       PlayerStartFuel,
+		TowingID:   -1,
+		BoardingID: -1,
+	}
+	s.ActionPoints = ShipActionPoints(MotherShip)
+	if m.Config.Elimination {
+		s.Health = ShipHealth(MotherShip)
 	}
 	p.MotherShip = s
 
@@ -112,3 +136,137 @@ func NewPlayer(m *Map, name string) *Player {
 	m.Players = append(m.Players, p)
 	return p
 }
+
+// AssignTeams puts players into teams listed in the game config.
+// Players not mentioned in any team play alone in a team of their own.
+func AssignTeams(m *Map) {
+	for team, members := range m.Config.Teams {
+		for _, name := range members {
+			for _, p := range m.Players {
+				if p.Name == name {
+					p.Team = team
+				}
+			}
+		}
+	}
+
+	nextTeam := len(m.Config.Teams)
+	for _, p := range m.Players {
+		if p.Team == -1 {
+			p.Team = nextTeam
+			nextTeam++
+		}
+	}
+}
+
+// Allied reports whether two players are on the same team. A player is always allied with itself.
+func (m *Map) Allied(playerA, playerB int) bool {
+	if playerA < 0 || playerA >= len(m.Players) || playerB < 0 || playerB >= len(m.Players) {
+		return false
+	}
+	return m.Players[playerA].Team == m.Players[playerB].Team
+}
+
+// EliminatePlayer removes the player from the game after their mothership was destroyed.
+// All their ships are wrecked and their asteroids lose their owner, the score is frozen.
+func EliminatePlayer(m *Map, p *Player) {
+	m.runner.Log(fmt.Sprintf("player %d (%v) eliminated in round %d", p.ID, p.Name, m.Round))
+
+	p.Alive = false
+	p.EliminatedRound = m.Round
+
+	for _, ship := range m.Ships {
+		if ship != nil && ship.PlayerID == p.ID && !ship.IsDestroyed {
+			DestroyShip(m, ship)
+		}
+	}
+
+	for _, asteroid := range m.Asteroids {
+		if asteroid != nil && asteroid.OwnerID == p.ID {
+			asteroid.OwnerID = -1
+			asteroid.OwnedSurface = 0
+		}
+	}
+}
+
+// TeamEliminatedRound returns the round in which the last player of the team was eliminated,
+// or -1 if some player of the team is still in the game
+func TeamEliminatedRound(m *Map, team int) int {
+	round := -1
+	for _, p := range m.Players {
+		if p.Team != team {
+			continue
+		}
+		if p.EliminatedRound == -1 {
+			return -1
+		}
+		round = max(round, p.EliminatedRound)
+	}
+	return round
+}
+
+// Teams returns the teams of all players, each team once
+func Teams(m *Map) []int {
+	var teams []int
+	seen := map[int]bool{}
+	for _, p := range m.Players {
+		if !seen[p.Team] {
+			seen[p.Team] = true
+			teams = append(teams, p.Team)
+		}
+	}
+	return teams
+}
+
+// RemainingTeams returns the number of teams which still have a player in the game
+func RemainingTeams(m *Map) int {
+	remaining := 0
+	for _, team := range Teams(m) {
+		if TeamEliminatedRound(m, team) == -1 {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// PlacementBonus rewards the team for every other team eliminated before it
+func PlacementBonus(m *Map, team int) int {
+	if !m.Config.Elimination {
+		return 0
+	}
+
+	eliminated := TeamEliminatedRound(m, team)
+	outlasted := 0
+	for _, other := range Teams(m) {
+		round := TeamEliminatedRound(m, other)
+		if other != team && round != -1 && (eliminated == -1 || round < eliminated) {
+			outlasted++
+		}
+	}
+	return outlasted * EliminationPlacementBonus
+}
+
+func TeamScore(m *Map, team int) int {
+	score := 0
+	for _, p := range m.Players {
+		if p.Team == team {
+			score += p.Score
+		}
+	}
+	return score
+}
+
+// FinalScores returns the scores reported to the runner. In team mode every player
+// receives the combined score of their team. In elimination mode the placement bonus is added.
+func FinalScores(m *Map) map[string]int {
+	scores := map[string]int{}
+	for _, p := range m.Players {
+		if m.Config.TeamMode() {
+			scores[p.Name] = TeamScore(m, p.Team)
+		} else {
+			scores[p.Name] = p.Score
+		}
+		scores[p.Name] += PlacementBonus(m, p.Team)
+	}
+	return scores
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"math"
-	"math/rand"
 )
 
 type Position struct {
@@ -28,20 +27,34 @@ func (p Position) Size() float64 {
 
 func RandomPosition(m *Map) Position {
 	return Position{
-		rand.Float64()*m.Radius*2 - m.Radius,
-		rand.Float64()*m.Radius*2 - m.Radius,
+		rng.Float64()*m.Radius*2 - m.Radius,
+		rng.Float64()*m.Radius*2 - m.Radius,
 	}
 }
 
 func RandomOffsetPosition(original Position, maxOffset float64) Position {
-	angle := rand.Float64() * 2 * math.Pi
-	distance := rand.Float64() * maxOffset
+	angle := rng.Float64() * 2 * math.Pi
+	distance := rng.Float64() * maxOffset
 	return Position{
 		original.X + distance*math.Cos(angle),
 		original.Y + distance*math.Sin(angle),
 	}
 }
 
+// RandomPositionInSector returns a uniformly distributed position in the circular sector
+// around the map center between the two angles
+func RandomPositionInSector(radius, fromAngle, toAngle float64) Position {
+	angle := RandomFloat(fromAngle, toAngle)
+	distance := radius * math.Sqrt(rng.Float64())
+	return Position{distance * math.Cos(angle), distance * math.Sin(angle)}
+}
+
+// Rotate rotates the position around the map center
+func (p Position) Rotate(angle float64) Position {
+	sin, cos := math.Sincos(angle)
+	return Position{p.X*cos - p.Y*sin, p.X*sin + p.Y*cos}
+}
+
 func (p Position) Scale(factor float64) Position {
 	return Position{p.X * factor, p.Y * factor}
 }
@@ -53,3 +66,16 @@ func (p Position) Normalize() Position {
 	}
 	return Position{p.X / size, p.Y / size}
 }
+
+// DistanceToSegment returns the distance from the point to the closest point of the segment a-b
+func (p Position) DistanceToSegment(a, b Position) float64 {
+	segment := b.Sub(a)
+	lengthSquared := segment.X*segment.X + segment.Y*segment.Y
+	if lengthSquared == 0 {
+		return p.Distance(a)
+	}
+
+	t := ((p.X-a.X)*segment.X + (p.Y-a.Y)*segment.Y) / lengthSquared
+	t = max(0, min(1, t))
+	return p.Distance(a.Add(segment.Scale(t)))
+}
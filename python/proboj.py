@@ -1,11 +1,19 @@
 import json
 import math
 import sys
-from dataclasses import dataclass
+from dataclasses import dataclass, field
 from enum import Enum
 from typing import List, Optional, Union, Dict, Any, TypeAlias
 
 
+SHIP_SHOOT_COOLDOWN = 3  # Counted down in the shot's round too, so 2 rounds of waiting
+SHIP_MAX_AMMO = 50  # Maximum ammo a ship can carry
+AMMO_ROCK_PRICE = 2  # Rock cost of a single unit of ammo
+SHIP_SHOT_HEAT = 25  # Heat generated by a single shot
+SHIP_MAX_HEAT = 100  # Ship can't shoot if the shot would raise its heat above this
+SHIP_HEAT_DISSIPATION = 10  # Heat lost by a ship every round
+
+
 class ShipType(Enum):
     MOTHER_SHIP = 0
     SUCKER_SHIP = 1
@@ -13,6 +21,7 @@ class ShipType(Enum):
     TANKER_SHIP = 3
     TRUCK_SHIP = 4
     BATTLE_SHIP = 5
+    SCOUT_SHIP = 6
 
 
 class AsteroidType(Enum):
@@ -20,6 +29,12 @@ class AsteroidType(Enum):
     FUEL_ASTEROID = 1
 
 
+class EventType(Enum):
+    SOLAR_STORM = 0
+    METEOR_SHOWER = 1
+    ION_CLOUD = 2
+
+
 class TurnType(Enum):
     BUY_TURN = 0
     MOVE_TURN = 1
@@ -27,6 +42,20 @@ class TurnType(Enum):
     SIPHON_TURN = 3
     SHOOT_TURN = 4
     REPAIR_TURN = 5
+    MINE_TURN = 6
+    CLOAK_TURN = 7
+    DECOMMISSION_TURN = 8
+    AMMO_TURN = 9
+    SHOOT_ASTEROID_TURN = 10
+    TOW_TURN = 11
+    BOARD_TURN = 12
+    NAVIGATE_TURN = 13
+    FLEET_TURN = 14
+    FLEET_MOVE_TURN = 15
+    FLEET_ATTACK_TURN = 16
+    ORDER_TURN = 17
+    TRANSACTION_TURN = 18
+    MESSAGE_TURN = 19
 
 
 @dataclass
@@ -55,6 +84,16 @@ class Position:
             return Position(0, 0)
         return Position(self.x / size, self.y / size)
 
+    def distance_to_segment(self, a: "Position", b: "Position") -> float:
+        """Distance from this point to the closest point of the segment a-b."""
+        segment = b.sub(a)
+        length_squared = segment.x**2 + segment.y**2
+        if length_squared == 0:
+            return self.distance(a)
+        t = ((self.x - a.x) * segment.x + (self.y - a.y) * segment.y) / length_squared
+        t = max(0.0, min(1.0, t))
+        return self.distance(a.add(segment.scale(t)))
+
     def to_dict(self) -> Dict[str, float]:
         return {"x": self.x, "y": self.y}
 
@@ -69,6 +108,96 @@ class Position:
         return obj
 
 
+@dataclass
+class Navigation:
+    """Autopilot order steering the ship towards a position, asteroid or friendly ship."""
+
+    target: Position
+    asteroid_id: int
+    target_ship_id: int
+    speed: float
+    arrival_speed: float
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "Navigation":
+        return cls(
+            Position.from_dict(data["target"]),
+            data["asteroid_id"],
+            data["target_ship_id"],
+            data["speed"],
+            data["arrival_speed"],
+        )
+
+
+@dataclass
+class StandingOrder:
+    """Mining or hauling loop executed by the server every round until it is cancelled or impossible."""
+
+    type: str  # "mine" or "ferry"
+    asteroid_id: int
+    source_id: int
+    destination_id: int
+    resource: str  # "rock" or "fuel"
+    amount: int
+    unloading: bool
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "StandingOrder":
+        return cls(
+            data["type"],
+            data["asteroid_id"],
+            data["source_id"],
+            data["destination_id"],
+            data["resource"],
+            data["amount"],
+            data["unloading"],
+        )
+
+
+@dataclass
+class OrderReport:
+    """Reason why the server stopped executing a standing order of our ship."""
+
+    ship_id: int
+    type: str
+    round: int
+    reason: str
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "OrderReport":
+        return cls(data["ship_id"], data["type"], data["round"], data["reason"])
+
+
+@dataclass
+class TransactionReport:
+    """Transaction which was rolled back because one of its turns failed."""
+
+    index: int  # position of the transaction in the turns we sent
+    turn: int  # position of the failed turn within the transaction
+    type: Optional[TurnType]  # None if the failed turn didn't have a valid type
+    reason: str
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "TransactionReport":
+        valid = data["type"] in [t.value for t in TurnType]
+        turn_type = TurnType(data["type"]) if valid else None
+        return cls(data["index"], data["turn"], turn_type, data["reason"])
+
+
+@dataclass
+class Message:
+    """Message from another player, sent to us or to everybody (to == -1) last round."""
+
+    round: int
+    sender: int
+    to: int
+    payload: Any  # any JSON value, usually a string
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "Message":
+        return cls(data["round"], data["from"], data["to"], data["payload"])
+
+
 @dataclass
 class Ship:
     id: int
@@ -80,6 +209,16 @@ class Ship:
     type: ShipType
     rock: int
     is_destroyed: bool = False
+    cloaked: bool = False
+    cooldown: int = 0
+    ammo: int = 0
+    heat: int = 0
+    towing_id: int = -1
+    boarding_id: int = -1
+    boarding_progress: int = 0
+    action_points: int = 0  # Action points left for this round
+    navigation: Optional[Navigation] = None
+    order: Optional[StandingOrder] = None
 
     def update_from_dict(self, data: Dict[str, Any]) -> None:
         self.id = data["id"]
@@ -91,11 +230,32 @@ class Ship:
         self.type = ShipType(data["type"])
         self.rock = data["rock"]
         self.is_destroyed = data.get("is_destroyed", False)
+        self.cloaked = data.get("cloaked", False)
+        self.cooldown = data.get("cooldown", 0)
+        self.ammo = data.get("ammo", 0)
+        self.heat = data.get("heat", 0)
+        self.towing_id = data.get("towing_id", -1)
+        self.boarding_id = data.get("boarding_id", -1)
+        self.boarding_progress = data.get("boarding_progress", 0)
+        self.action_points = data.get("action_points", 0)
+        navigation = data.get("navigation")
+        self.navigation = Navigation.from_dict(navigation) if navigation else None
+        order = data.get("order")
+        self.order = StandingOrder.from_dict(order) if order else None
 
     @classmethod
     def from_dict(cls, data: Dict[str, Any]) -> "Ship":
         obj = cls(
-            0, 0, Position(0, 0), Position(0, 0), 0, 0, ShipType.MOTHER_SHIP, 0, False
+            0,
+            0,
+            Position(0, 0),
+            Position(0, 0),
+            0,
+            0,
+            ShipType.MOTHER_SHIP,
+            0,
+            False,
+            False,
         )
         obj.update_from_dict(data)
         return obj
@@ -109,8 +269,14 @@ class Ship:
         return not self.is_destroyed
 
     def can_shoot(self) -> bool:
-        """Check if the ship can shoot (BattleShip and not destroyed)."""
-        return self.type == ShipType.BATTLE_SHIP and not self.is_destroyed
+        """Check if the ship can shoot (BattleShip, not destroyed, weapon ready and loaded)."""
+        return (
+            self.type == ShipType.BATTLE_SHIP
+            and not self.is_destroyed
+            and self.cooldown == 0
+            and self.ammo > 0
+            and self.heat + SHIP_SHOT_HEAT <= SHIP_MAX_HEAT
+        )
 
     def can_mine(self) -> bool:
         """Check if the ship can mine (DrillShip or SuckerShip and not destroyed)."""
@@ -118,6 +284,10 @@ class Ship:
             self.type == ShipType.DRILL_SHIP or self.type == ShipType.SUCKER_SHIP
         ) and not self.is_destroyed
 
+    def can_cloak(self) -> bool:
+        """Check if the ship can toggle its cloak (ScoutShip and not destroyed)."""
+        return self.type == ShipType.SCOUT_SHIP and not self.is_destroyed
+
     def can_carry_cargo(self) -> bool:
         """Check if the ship can carry cargo (TankerShip or TruckShip and not destroyed)."""
         return (
@@ -133,6 +303,10 @@ class Asteroid:
     size: float
     owner_id: int
     surface: float
+    is_wreck: bool = False
+    vector: Position = field(default_factory=lambda: Position(0, 0))
+    weight: float = 1.0
+    damage: float = 0.0
 
     def update_from_dict(self, data: Dict[str, Any]) -> None:
         self.id = data["id"]
@@ -141,6 +315,10 @@ class Asteroid:
         self.size = data["size"]
         self.owner_id = data["owner_id"]
         self.surface = data["surface"]
+        self.is_wreck = data.get("is_wreck", False)
+        self.vector.update_from_dict(data.get("vector", {"x": 0, "y": 0}))
+        self.weight = data.get("weight", 1.0)
+        self.damage = data.get("damage", 0.0)
 
     @classmethod
     def from_dict(cls, data: Dict[str, Any]) -> "Asteroid":
@@ -167,6 +345,85 @@ class Wormhole:
         return obj
 
 
+@dataclass
+class Mine:
+    id: int
+    player_id: int
+    position: Position
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "Mine":
+        return cls(data["id"], data["player"], Position.from_dict(data["position"]))
+
+
+@dataclass
+class Event:
+    id: int
+    type: EventType
+    position: Position
+    radius: float
+    start_round: int
+    end_round: int
+
+    def is_active(self, round: int) -> bool:
+        """Check if the event is in effect in the given round."""
+        return self.start_round <= round <= self.end_round
+
+    def covers(self, position: Position) -> bool:
+        """Check if the position is inside the event area (radius 0 means the whole map)."""
+        return self.radius == 0 or self.position.distance(position) <= self.radius
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "Event":
+        return cls(
+            data["id"],
+            EventType(data["type"]),
+            Position.from_dict(data["position"]),
+            data["radius"],
+            data["start_round"],
+            data["end_round"],
+        )
+
+
+@dataclass
+class Zone:
+    id: int
+    position: Position
+    radius: float
+    vector: Position
+    owner_id: int
+    contested: bool
+
+    def contains(self, position: Position) -> bool:
+        """Check if the position is inside the control zone."""
+        return self.position.distance(position) <= self.radius
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "Zone":
+        return cls(
+            data["id"],
+            Position.from_dict(data["position"]),
+            data["radius"],
+            Position.from_dict(data["vector"]),
+            data["owner_id"],
+            data["contested"],
+        )
+
+
+@dataclass
+class Fleet:
+    """Named group of our ships, only our own fleets are sent to us."""
+
+    name: str
+    ship_ids: List[int]
+    formation: str
+    spacing: float
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "Fleet":
+        return cls(data["name"], data["ship_ids"], data["formation"], data["spacing"])
+
+
 @dataclass
 class Player:
     id: int
@@ -175,6 +432,8 @@ class Player:
     rock: int
     fuel: int
     alive: bool
+    team: int
+    eliminated_round: int
 
     def update_from_dict(self, data: Dict[str, Any]) -> None:
         self.id = data["id"]
@@ -183,10 +442,12 @@ class Player:
         self.rock = data["mothership"]["rock"]
         self.fuel = data["mothership"]["fuel"]
         self.alive = data["alive"]
+        self.team = data["team"]
+        self.eliminated_round = data["eliminated_round"]
 
     @classmethod
     def from_dict(cls, data: Dict[str, Any]) -> "Player":
-        obj = cls(0, "", "", 0, 0, False)
+        obj = cls(0, "", "", 0, 0, False, 0, -1)
         obj.update_from_dict(data)
         return obj
 
@@ -200,6 +461,9 @@ class GameMap:
     players: List[Optional[Player]]
     round: int
     my_player_id: int
+    events: List[Event]
+    mines: List[Optional[Mine]]
+    zones: List[Zone]
 
     def _update_ships(self, ships_data: List[Optional[Dict[str, Any]]]) -> None:
         # Ensure list is correct length
@@ -260,6 +524,72 @@ class GameMap:
         self._update_asteroids(data["asteroids"])
         self._update_wormholes(data["wormholes"])
         self._update_players(data["players"])
+        self._update_lists(data)
+
+    def _update_lists(self, data: Dict[str, Any]) -> None:
+        # Events are short-lived, so they are simply replaced every round
+        self.events = [Event.from_dict(e) for e in data.get("events") or []]
+        # Mines never move, enemy mines are only sent while our ships are close to them
+        self.mines = [
+            Mine.from_dict(m) if m is not None else None
+            for m in data.get("mines") or []
+        ]
+        self.zones = [Zone.from_dict(z) for z in data.get("zones") or []]
+
+    def _apply_delta(self, delta: Dict[str, Any]) -> None:
+        """Apply the changes sent instead of the full map with the delta_states capability."""
+        self.round = delta["round"]
+        for entities, cls, changed, removed in (
+            (self.ships, Ship, delta["ships"], delta["removed_ships"]),
+            (self.asteroids, Asteroid, delta["asteroids"], delta["removed_asteroids"]),
+        ):
+            for entity_data in changed:
+                entity_id = entity_data["id"]
+                while len(entities) <= entity_id:
+                    entities.append(None)
+                if entities[entity_id] is None:
+                    entities[entity_id] = cls.from_dict(entity_data)
+                else:
+                    entities[entity_id].update_from_dict(entity_data)
+            for entity_id in removed:
+                entities[entity_id] = None
+        for player_data in delta["players"]:
+            self.players[player_data["id"]].update_from_dict(player_data)
+        self._update_lists(delta)
+
+    def state_hash(self) -> str:
+        """FNV-1a hash of the entities we know about, the server sends the same one."""
+
+        def f(x: float) -> str:
+            return f"{x:.3f}"
+
+        ships = "|".join(
+            f"{s.id},{s.player_id},{s.type.value},{f(s.position.x)},{f(s.position.y)},"
+            f"{f(s.vector.x)},{f(s.vector.y)},{s.health},{f(s.fuel)},{s.rock},"
+            f"{int(s.is_destroyed)}"
+            for s in self.ships
+            if s is not None
+        )
+        asteroids = "|".join(
+            f"{a.id},{a.type.value},{a.owner_id},{f(a.position.x)},{f(a.position.y)},"
+            f"{f(a.size)},{f(a.surface)}"
+            for a in self.asteroids
+            if a is not None
+        )
+        players = "|".join(
+            f"{p.id},{int(p.alive)},{p.eliminated_round}"
+            for p in self.players
+            if p is not None
+        )
+        text = (
+            f"round:{self.round};ships:{ships};"
+            f"asteroids:{asteroids};players:{players}"
+        )
+        h = 0xCBF29CE484222325
+        for byte in text.encode():
+            h ^= byte
+            h = (h * 0x100000001B3) & 0xFFFFFFFFFFFFFFFF
+        return f"{h:016x}"
 
     @classmethod
     def from_dict(cls, data: Dict[str, Any]) -> "GameMap":
@@ -269,7 +599,7 @@ class GameMap:
         wormholes: List[Optional[Wormhole]] = [None] * len(data["wormholes"])
         players: List[Optional[Player]] = [None] * len(data["players"])
 
-        obj = cls(0, ships, asteroids, wormholes, players, 0, 0)
+        obj = cls(0, ships, asteroids, wormholes, players, 0, 0, [], [], [])
 
         # Now use update logic to populate it
         obj._update_from_dict(data)
@@ -356,14 +686,265 @@ class RepairTurn:
         return {"type": TurnType.REPAIR_TURN.value, "data": {"ship_id": self.ship_id}}
 
 
+@dataclass
+class MineTurn:
+    ship_id: int
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {"type": TurnType.MINE_TURN.value, "data": {"ship_id": self.ship_id}}
+
+
+@dataclass
+class CloakTurn:
+    ship_id: int
+    enabled: bool
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.CLOAK_TURN.value,
+            "data": {"ship_id": self.ship_id, "enabled": self.enabled},
+        }
+
+
+@dataclass
+class DecommissionTurn:
+    ship_id: int
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.DECOMMISSION_TURN.value,
+            "data": {"ship_id": self.ship_id},
+        }
+
+
+@dataclass
+class AmmoTurn:
+    ship_id: int
+    amount: int
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.AMMO_TURN.value,
+            "data": {"ship_id": self.ship_id, "amount": self.amount},
+        }
+
+
+@dataclass
+class ShootAsteroidTurn:
+    source_id: int
+    asteroid_id: int
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.SHOOT_ASTEROID_TURN.value,
+            "data": {"source_id": self.source_id, "asteroid_id": self.asteroid_id},
+        }
+
+
+@dataclass
+class TowTurn:
+    ship_id: int
+    asteroid_id: int  # -1 releases the towed asteroid
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.TOW_TURN.value,
+            "data": {"ship_id": self.ship_id, "asteroid_id": self.asteroid_id},
+        }
+
+
+@dataclass
+class BoardTurn:
+    source_id: int
+    destination_id: int
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.BOARD_TURN.value,
+            "data": {
+                "source_id": self.source_id,
+                "destination_id": self.destination_id,
+            },
+        }
+
+
+@dataclass
+class NavigateTurn:
+    """Autopilot order, set exactly one of the targets. Without a target the current order is cancelled."""
+
+    ship_id: int
+    target: Optional[Position] = None
+    asteroid_id: Optional[int] = None
+    target_ship_id: Optional[int] = None
+    speed: float = 0.0  # 0 uses the default cruise speed
+    arrival_speed: float = 0.0
+
+    def to_dict(self) -> Dict[str, Any]:
+        data: Dict[str, Any] = {"ship_id": self.ship_id}
+        if self.target is not None:
+            data["target"] = self.target.to_dict()
+        if self.asteroid_id is not None:
+            data["asteroid_id"] = self.asteroid_id
+        if self.target_ship_id is not None:
+            data["target_ship_id"] = self.target_ship_id
+        if self.speed:
+            data["speed"] = self.speed
+        if self.arrival_speed:
+            data["arrival_speed"] = self.arrival_speed
+        return {"type": TurnType.NAVIGATE_TURN.value, "data": data}
+
+
+@dataclass
+class FleetTurn:
+    """Create or replace a fleet (formation is "", "line", "column", "wedge" or "circle"), or disband it."""
+
+    name: str
+    ship_ids: List[int] = field(default_factory=list)
+    formation: str = ""
+    spacing: float = 0.0  # 0 uses the default spacing
+    disband: bool = False
+
+    def to_dict(self) -> Dict[str, Any]:
+        data: Dict[str, Any] = {"name": self.name}
+        if self.disband:
+            data["disband"] = True
+        else:
+            data["ship_ids"] = self.ship_ids
+            if self.formation:
+                data["formation"] = self.formation
+            if self.spacing:
+                data["spacing"] = self.spacing
+        return {"type": TurnType.FLEET_TURN.value, "data": data}
+
+
+@dataclass
+class FleetMoveTurn:
+    """Send every ship of the fleet to its place in the formation around the target."""
+
+    fleet: str
+    target: Position
+    speed: float = 0.0
+    arrival_speed: float = 0.0
+
+    def to_dict(self) -> Dict[str, Any]:
+        data: Dict[str, Any] = {"fleet": self.fleet, "target": self.target.to_dict()}
+        if self.speed:
+            data["speed"] = self.speed
+        if self.arrival_speed:
+            data["arrival_speed"] = self.arrival_speed
+        return {"type": TurnType.FLEET_MOVE_TURN.value, "data": data}
+
+
+@dataclass
+class FleetAttackTurn:
+    """Every BattleShip of the fleet shoots at the destination ship."""
+
+    fleet: str
+    destination_id: int
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.FLEET_ATTACK_TURN.value,
+            "data": {"fleet": self.fleet, "destination_id": self.destination_id},
+        }
+
+
+@dataclass
+class OrderTurn:
+    """Standing order: "mine" the asteroid and unload at the destination (our mothership by default),
+    or "ferry" the resource from the source ship to the destination. Without an order the current one is cancelled."""
+
+    ship_id: int
+    order: str = ""
+    asteroid_id: Optional[int] = None
+    source_id: Optional[int] = None
+    destination_id: Optional[int] = None
+    resource: str = ""  # ferry orders carry rock by default
+    amount: int = 0  # 0 uses the default amount
+
+    def to_dict(self) -> Dict[str, Any]:
+        data: Dict[str, Any] = {"ship_id": self.ship_id}
+        if self.order:
+            data["order"] = self.order
+        if self.asteroid_id is not None:
+            data["asteroid_id"] = self.asteroid_id
+        if self.source_id is not None:
+            data["source_id"] = self.source_id
+        if self.destination_id is not None:
+            data["destination_id"] = self.destination_id
+        if self.resource:
+            data["resource"] = self.resource
+        if self.amount:
+            data["amount"] = self.amount
+        return {"type": TurnType.ORDER_TURN.value, "data": data}
+
+
+@dataclass
+class TransactionTurn:
+    """Turns which either all succeed, or are all rolled back. Transactions can't be nested."""
+
+    turns: List["Turn"]
+
+    def to_dict(self) -> Dict[str, Any]:
+        return {
+            "type": TurnType.TRANSACTION_TURN.value,
+            "data": {"turns": [turn.to_dict() for turn in self.turns]},
+        }
+
+
+@dataclass
+class MessageTurn:
+    """Send a JSON value (at most 1024 bytes) to the player, or to everybody if `to` is None."""
+
+    payload: Any
+    to: Optional[int] = None
+
+    def to_dict(self) -> Dict[str, Any]:
+        data: Dict[str, Any] = {"payload": self.payload}
+        if self.to is not None:
+            data["to"] = self.to
+        return {"type": TurnType.MESSAGE_TURN.value, "data": data}
+
+
 # Type alias for all possible turn types
-Turn: TypeAlias = Union[BuyTurn, MoveTurn, LoadTurn, SiphonTurn, ShootTurn, RepairTurn]
+Turn: TypeAlias = Union[
+    BuyTurn,
+    MoveTurn,
+    LoadTurn,
+    SiphonTurn,
+    ShootTurn,
+    RepairTurn,
+    MineTurn,
+    CloakTurn,
+    DecommissionTurn,
+    AmmoTurn,
+    ShootAsteroidTurn,
+    TowTurn,
+    BoardTurn,
+    NavigateTurn,
+    FleetTurn,
+    FleetMoveTurn,
+    FleetAttackTurn,
+    OrderTurn,
+    TransactionTurn,
+    MessageTurn,
+]
 
 
 class Client:
     def __init__(self):
         self.game_map: Optional[GameMap] = None
         self.my_player_id: Optional[int] = None
+        self.fleets: Dict[str, Fleet] = {}
+        self.reports: List[OrderReport] = []
+        self.failed_transactions: List[TransactionReport] = []
+        self.messages: List[Message] = []
+        self.debug: List[Dict[str, Any]] = []
+        # Protocol version, game config, ship specs and features from the first state
+        self.handshake: Optional[Dict[str, Any]] = None
+        self.accepted_capabilities: List[str] = []
+        # Set when our delta-updated map got out of sync, asks for a full state
+        self.resync = False
 
     def log(self, *args, **kwargs):
         kwargs["file"] = sys.stderr
@@ -372,12 +953,30 @@ class Client:
     def load_game_state(self, json_data: str) -> None:
         data = json.loads(json_data)
 
-        if self.game_map is None:
+        if "delta" in data and self.game_map is not None:
+            self.game_map._apply_delta(data["delta"])
+        elif self.game_map is None:
             self.game_map = GameMap.from_dict(data["map"])
         else:
             self.game_map._update_from_dict(data["map"])
 
+        if "hash" in data and self.game_map.state_hash() != data["hash"]:
+            self.log("state out of sync with the server, asking for a full state")
+            self.resync = True
+
         self.my_player_id = data["player_id"]
+        self.fleets = {
+            name: Fleet.from_dict(f) for name, f in (data.get("fleets") or {}).items()
+        }
+        self.reports = [OrderReport.from_dict(r) for r in data.get("reports") or []]
+        self.failed_transactions = [
+            TransactionReport.from_dict(r)
+            for r in data.get("failed_transactions") or []
+        ]
+        self.messages = [Message.from_dict(m) for m in data.get("messages") or []]
+        if data.get("handshake"):
+            self.handshake = data["handshake"]
+        self.accepted_capabilities = data.get("capabilities") or []
 
     def get_my_player(self) -> Optional[Player]:
         if self.game_map is None or self.my_player_id is None:
@@ -394,6 +993,33 @@ class Client:
                 my_ships.append(ship)
         return my_ships
 
+    def is_ally(self, player_id: int) -> bool:
+        """Check if the given player is on the same team as us (we are our own ally)."""
+        if self.game_map is None or self.my_player_id is None:
+            return False
+        me = self.game_map.players[self.my_player_id]
+        other = self.game_map.players[player_id]
+        return me is not None and other is not None and me.team == other.team
+
+    def first_asteroid_on_line(
+        self, source: Position, target: Position
+    ) -> Optional[Asteroid]:
+        """Asteroid that would block a shot from source to target when line of sight is enabled."""
+        if self.game_map is None:
+            return None
+
+        blocker = None
+        for asteroid in self.game_map.asteroids:
+            if asteroid is None or source.distance(asteroid.position) <= asteroid.size:
+                continue
+            if asteroid.position.distance_to_segment(source, target) > asteroid.size:
+                continue
+            if blocker is None or source.distance(asteroid.position) < source.distance(
+                blocker.position
+            ):
+                blocker = asteroid
+        return blocker
+
     def get_my_mothership(self) -> Optional[Ship]:
         if self.game_map is None or self.my_player_id is None:
             return None
@@ -407,6 +1033,54 @@ class Client:
                 return ship
         return None
 
+    def _debug_point(self, key: str, point: Union[Position, int]) -> Dict[str, Any]:
+        """Debug shapes can be tied to a position, or to a ship given by its ID."""
+        if isinstance(point, Position):
+            return {key: point.to_dict()}
+        return {"ship_id" if key == "position" else "to_ship_id": point}
+
+    def _debug_shape(self, shape: Dict[str, Any], color: Optional[str]) -> None:
+        if color:
+            shape["color"] = color
+        self.debug.append(shape)
+
+    def debug_line(
+        self,
+        start: Union[Position, int],
+        end: Union[Position, int],
+        color: Optional[str] = None,
+    ) -> None:
+        """Draw a line in the observer this round, ends are positions or ship IDs."""
+        shape = {
+            "type": "line",
+            **self._debug_point("position", start),
+            **self._debug_point("to", end),
+        }
+        self._debug_shape(shape, color)
+
+    def debug_circle(
+        self, center: Union[Position, int], radius: float, color: Optional[str] = None
+    ) -> None:
+        """Draw a circle in the observer this round."""
+        shape = {
+            "type": "circle",
+            **self._debug_point("position", center),
+            "radius": radius,
+        }
+        self._debug_shape(shape, color)
+
+    def debug_text(
+        self, at: Union[Position, int], text: str, color: Optional[str] = None
+    ) -> None:
+        """Draw a label (at most 100 characters) in the observer this round."""
+        shape = {"type": "text", **self._debug_point("position", at), "text": text}
+        self._debug_shape(shape, color)
+
+    def capabilities(self) -> List[str]:
+        """Protocol extensions this bot supports, declared in the reply to the handshake.
+        Override it to opt in, e.g. return ["delta_states"] to receive only changes of the map."""
+        return []
+
     def turn(self) -> List[Turn]:
         return []
 
@@ -416,7 +1090,21 @@ class Client:
             assert input() == "."
 
             self.load_game_state(line)
+            handshake = self.game_map is not None and self.game_map.round == 0
             turns = self.turn()
-            turns_data = [turn.to_dict() for turn in turns]
-            print(json.dumps(turns_data), flush=True)
+            response: Dict[str, Any] = {"turns": [turn.to_dict() for turn in turns]}
+            if self.debug:
+                response["debug"] = self.debug
+                self.debug = []
+            if handshake and self.handshake is not None and self.capabilities():
+                response["capabilities"] = self.capabilities()
+            if self.resync:
+                response["resync"] = True
+                self.resync = False
+
+            # Bare list of turns when there is nothing else to send
+            if len(response) == 1:
+                print(json.dumps(response["turns"]), flush=True)
+            else:
+                print(json.dumps(response), flush=True)
             print(".", flush=True)
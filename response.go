@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PlayerResponse is what a bot sends back every round. Bots may send either a bare list
+// of turns, or an object with the turns and optional extras.
+type PlayerResponse struct {
+	Turns []TurnContainer `json:"turns"`
+	Debug []DebugShape    `json:"debug,omitempty"` // Annotations for the observer, see DebugShape
+	// Protocol extensions the bot supports, replaces the previously declared ones
+	Capabilities []Capability `json:"capabilities,omitempty"`
+	// Asks for a full state in the next round, used with delta states after a desync
+	Resync bool `json:"resync,omitempty"`
+}
+
+func ParsePlayerResponse(data string) (PlayerResponse, error) {
+	var response PlayerResponse
+
+	data = strings.TrimSpace(data)
+	if strings.HasPrefix(data, "[") {
+		err := json.Unmarshal([]byte(data), &response.Turns)
+		return response, err
+	}
+
+	err := json.Unmarshal([]byte(data), &response)
+	return response, err
+}
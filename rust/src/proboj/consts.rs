@@ -11,6 +11,7 @@ pub const WORMHOLE_TELEPORT_DISTANCE: f64 = WORMHOLE_RADIUS * 2.0; // Minimum di
 
 pub const SHIP_MINING_DISTANCE: f64 = MAX_ASTEROID_SIZE; // Maximum distance for mining operations
 pub const SHIP_MINING_AMOUNT: f64 = 10.0; // Units mined per tick
+pub const SHIP_SALVAGE_AMOUNT: f64 = 20.0; // Units salvaged per tick from wreck asteroids
 pub const SHIP_CONQUERING_DISTANCE: f64 = MAX_ASTEROID_SIZE; // Maximum distance for conquering operations
 pub const SHIP_CONQUERING_RATE: f64 = 10.0; // Rate of conquering
 
@@ -21,6 +22,46 @@ pub const SHIP_MOVEMENT_MAX_SIZE: f64 = 10000.0; // Maximum movement delta per t
 pub const SHIP_TRANSFER_DISTANCE: f64 = 20.0; // Maximum distance for resource transfer between ships
 pub const SHIP_SHOOT_DISTANCE: f64 = 500.0; // Maximum shooting range for ships
 pub const SHIP_SHOOT_DAMAGE: f64 = 25.0; // Damage dealt by ship weapons
+pub const SHIP_SHOOT_COOLDOWN: i64 = 3; // Counted down in the shot's round too, so 2 rounds of waiting
+pub const SHIP_MAX_AMMO: i64 = 50; // Maximum ammo a ship can carry
+pub const AMMO_ROCK_PRICE: i64 = 2; // Rock cost of a single unit of ammo
+pub const SHIP_SHOT_HEAT: i64 = 25; // Heat generated by a single shot
+pub const SHIP_MAX_HEAT: i64 = 100; // Ship can't shoot if the shot would raise its heat above this
+pub const SHIP_HEAT_DISSIPATION: i64 = 10; // Heat lost by a ship every round
 pub const SHIP_REPAIR_DISTANCE: f64 = 50.0; // Maximum distance for ship repair operations
 pub const SHIP_REPAIR_AMOUNT: f64 = 30.0; // Health points restored by repair
 pub const SHIP_REPAIR_ROCK_COST: f64 = 15.0; // Rock cost per repair operation
+
+pub const EVENT_WARNING_ROUNDS: i64 = 10; // Number of rounds an event is announced before it starts
+pub const SOLAR_STORM_DAMAGE: f64 = 2.0; // Damage per round dealt by solar storms to uncovered ships
+pub const SOLAR_STORM_COVER_DISTANCE: f64 = 20.0; // Distance from asteroid surface within which ships are covered from solar storms
+pub const ION_CLOUD_MOVEMENT_MULTIPLIER: f64 = 2.0; // Fuel cost multiplier for movement inside ion clouds
+
+pub const MINE_ROCK_COST: i64 = 20; // Rock taken from the ship's cargo to lay a mine
+pub const MINE_TRIGGER_DISTANCE: f64 = 30.0; // Distance within which an enemy ship detonates a mine
+pub const MINE_BLAST_RADIUS: f64 = 60.0; // Radius of the area damaged by a mine detonation
+pub const MINE_DAMAGE: f64 = 40.0; // Damage dealt to every ship in the blast radius
+pub const MINE_VISIBILITY_DISTANCE: f64 = 100.0; // Distance within which enemy ships can see a mine
+
+pub const SCOUT_SHIP_MAX_HEALTH: f64 = 40.0; // Maximum health points for scout ships
+pub const SCOUT_SENSOR_RANGE: f64 = 1000.0; // Distance within which scouts detect cloaked ships and enemy mines
+pub const SCOUT_CLOAK_FUEL_COST: f64 = 2.0; // Fuel paid by a cloaked ship every round
+
+pub const DECOMMISSION_REFUND_RATIO: f64 = 0.5; // Part of the ship price refunded when it is decommissioned
+
+pub const ASTEROID_SPLIT_DAMAGE: f64 = 100.0; // Damage after which an asteroid splits into fragments
+pub const ASTEROID_SPLIT_MIN_SIZE: f64 = MIN_ASTEROID_SIZE; // Smaller asteroids don't split, they are only worn down
+pub const ASTEROID_SPLIT_FRAGMENTS: i64 = 3; // Number of fragments an asteroid splits into
+pub const ASTEROID_FRAGMENT_SPEED: f64 = 5.0; // Initial speed of fragments flying apart
+pub const ASTEROID_FRAGMENT_DRAG: f64 = 0.95; // Fraction of fragment speed kept every tick
+
+pub const SHIP_TOW_DISTANCE: f64 = MAX_ASTEROID_SIZE * 2.0; // Maximum distance for latching onto an asteroid
+pub const TOW_FUEL_PER_SIZE: f64 = 0.02; // Fuel per unit of distance and asteroid size paid while towing
+
+pub const SHIP_BOARDING_DISTANCE: f64 = SHIP_TRANSFER_DISTANCE; // Maximum distance between the boarding ship and its target
+pub const SHIP_BOARDING_HEALTH_THRESHOLD: i64 = 30; // Ships with more health can't be boarded
+pub const SHIP_BOARDING_ROUNDS: i64 = 5; // Rounds the boarding ship has to stay in range to capture the target
+
+pub const MOTHERSHIP_MAX_HEALTH: f64 = 1000.0; // Maximum health points for motherships in elimination mode
+pub const ORDER_DEFAULT_AMOUNT: i64 = 100; // Cargo carried by standing orders if the order doesn't set an amount
+pub const ORDER_FUEL_RESERVE: f64 = 50.0; // Fuel standing orders never unload, so the ship can keep flying
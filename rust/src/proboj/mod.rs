@@ -12,20 +12,61 @@ pub use consts::*;
 pub use types::*;
 pub use vec2d::Vec2D;
 
+fn turn_to_json(turn: Turn) -> serde_json::Value {
+    match turn {
+        Turn::BuyTurn(t) => serde_json::json!({"type": 0,  "data": t}),
+        Turn::MoveTurn(t) => serde_json::json!({"type": 1, "data": t}),
+        Turn::LoadTurn(t) => serde_json::json!({"type": 2, "data": t}),
+        Turn::SiphonTurn(t) => serde_json::json!({"type": 3, "data": t}),
+        Turn::ShootTurn(t) => serde_json::json!({"type": 4, "data": t}),
+        Turn::RepairTurn(t) => serde_json::json!({"type": 5, "data": t}),
+        Turn::MineTurn(t) => serde_json::json!({"type": 6, "data": t}),
+        Turn::CloakTurn(t) => serde_json::json!({"type": 7, "data": t}),
+        Turn::DecommissionTurn(t) => serde_json::json!({"type": 8, "data": t}),
+        Turn::AmmoTurn(t) => serde_json::json!({"type": 9, "data": t}),
+        Turn::ShootAsteroidTurn(t) => serde_json::json!({"type": 10, "data": t}),
+        Turn::TowTurn(t) => serde_json::json!({"type": 11, "data": t}),
+        Turn::BoardTurn(t) => serde_json::json!({"type": 12, "data": t}),
+        Turn::NavigateTurn(t) => serde_json::json!({"type": 13, "data": t}),
+        Turn::FleetTurn(t) => serde_json::json!({"type": 14, "data": t}),
+        Turn::FleetMoveTurn(t) => serde_json::json!({"type": 15, "data": t}),
+        Turn::FleetAttackTurn(t) => serde_json::json!({"type": 16, "data": t}),
+        Turn::OrderTurn(t) => serde_json::json!({"type": 17, "data": t}),
+        Turn::MessageTurn(t) => serde_json::json!({"type": 19, "data": t}),
+        Turn::TransactionTurn(turns) => {
+            let turns = turns.into_iter().map(turn_to_json).collect::<Vec<_>>();
+            serde_json::json!({"type": 18, "data": {"turns": turns}})
+        }
+    }
+}
+
 pub fn send_turns(turns: Vec<Turn>) {
-    let turns = turns
-        .into_iter()
-        .map(|turn| match turn {
-            Turn::BuyTurn(t) => serde_json::json!({"type": 0,  "data": t}),
-            Turn::MoveTurn(t) => serde_json::json!({"type": 1, "data": t}),
-            Turn::LoadTurn(t) => serde_json::json!({"type": 2, "data": t}),
-            Turn::SiphonTurn(t) => serde_json::json!({"type": 3, "data": t}),
-            Turn::ShootTurn(t) => serde_json::json!({"type": 4, "data": t}),
-            Turn::RepairTurn(t) => serde_json::json!({"type": 5, "data": t}),
-        })
-        .collect::<Vec<_>>();
+    send_turns_with_debug(turns, vec![]);
+}
+
+/// Sends the turns together with debug shapes drawn by the observer this round
+pub fn send_turns_with_debug(turns: Vec<Turn>, debug: Vec<DebugShape>) {
+    send_response(turns, debug, vec![]);
+}
+
+/// Sends the turns with debug shapes and the protocol extensions the bot supports,
+/// capabilities are usually declared in reply to the handshake
+pub fn send_response(turns: Vec<Turn>, debug: Vec<DebugShape>, capabilities: Vec<&str>) {
+    let turns = turns.into_iter().map(turn_to_json).collect::<Vec<_>>();
+
+    let mut response = serde_json::json!({"turns": turns});
+    if !debug.is_empty() {
+        response["debug"] = serde_json::json!(debug);
+    }
+    if !capabilities.is_empty() {
+        response["capabilities"] = serde_json::json!(capabilities);
+    }
 
-    let json = serde_json::to_string(&turns).unwrap();
+    let json = if debug.is_empty() && capabilities.is_empty() {
+        serde_json::to_string(&response["turns"]).unwrap()
+    } else {
+        serde_json::to_string(&response).unwrap()
+    };
     println!("{}", json);
     println!(".");
     stdout().flush().unwrap();
@@ -38,6 +79,20 @@ pub struct GameState {
     pub wormholes: HashMap<WormholeId, Wormhole>,
     pub players: HashMap<PlayerId, Player>,
     pub round: i64,
+    pub events: Vec<Event>,
+    pub mines: HashMap<MineId, Mine>,
+    pub zones: Vec<Zone>,
+    pub fleets: HashMap<String, Fleet>,
+    /// Standing orders which ended since the last round
+    pub reports: Vec<OrderReport>,
+    /// Transactions rolled back in the last round
+    pub failed_transactions: Vec<TransactionReport>,
+    /// Messages sent to us last round
+    pub messages: Vec<Message>,
+    /// Sent only in the first round
+    pub handshake: Option<Handshake>,
+    /// Capabilities we asked for which the server accepted
+    pub capabilities: Vec<String>,
     pub my_id: PlayerId,
 }
 
@@ -53,9 +108,30 @@ pub fn get_state() -> GameState {
     struct StateMessage {
         map: GameMap,
         player_id: PlayerId,
+        #[serde(default)]
+        fleets: Option<HashMap<String, Fleet>>,
+        #[serde(default)]
+        reports: Option<Vec<OrderReport>>,
+        #[serde(default)]
+        failed_transactions: Option<Vec<TransactionReport>>,
+        #[serde(default)]
+        messages: Option<Vec<Message>>,
+        #[serde(default)]
+        handshake: Option<Handshake>,
+        #[serde(default)]
+        capabilities: Option<Vec<String>>,
     }
 
-    let StateMessage { map, player_id } = serde_json::from_str(&input).unwrap();
+    let StateMessage {
+        map,
+        player_id,
+        fleets,
+        reports,
+        failed_transactions,
+        messages,
+        handshake,
+        capabilities,
+    } = serde_json::from_str(&input).unwrap();
 
     GameState {
         radius: map.radius,
@@ -84,6 +160,15 @@ pub fn get_state() -> GameState {
             .filter_map(|(i, player)| player.map(|p| (PlayerId(i), p)))
             .collect(),
         round: map.round,
+        events: map.events,
+        mines: map.mines.into_iter().flatten().map(|m| (m.id, m)).collect(),
+        zones: map.zones,
+        fleets: fleets.unwrap_or_default(),
+        reports: reports.unwrap_or_default(),
+        failed_transactions: failed_transactions.unwrap_or_default(),
+        messages: messages.unwrap_or_default(),
+        handshake,
+        capabilities: capabilities.unwrap_or_default(),
         my_id: player_id,
     }
 }
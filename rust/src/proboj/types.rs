@@ -1,6 +1,7 @@
 use super::Vec2D;
 use serde::{Deserialize, Serialize};
 use serde_repr::{Deserialize_repr, Serialize_repr};
+use std::collections::HashMap;
 
 #[repr(u8)]
 #[derive(Clone, Debug, Serialize_repr, Deserialize_repr)]
@@ -11,6 +12,7 @@ pub enum ShipType {
     TankerShip,
     TruckShip,
     BattleShip,
+    ScoutShip,
 }
 
 #[derive(Clone, Copy, Debug, Serialize, Deserialize, PartialEq, Eq, Hash)]
@@ -29,6 +31,133 @@ pub struct Ship {
     pub ship_type: ShipType,
     pub rock: i64,
     pub is_destroyed: bool,
+    #[serde(default)]
+    pub cloaked: bool,
+    #[serde(default)]
+    pub cooldown: i64,
+    #[serde(default)]
+    pub ammo: i64,
+    #[serde(default)]
+    pub heat: i64,
+    /// ID of the towed asteroid, -1 if none
+    #[serde(default)]
+    pub towing_id: i64,
+    /// ID of the enemy ship being boarded, -1 if none
+    #[serde(default)]
+    pub boarding_id: i64,
+    #[serde(default)]
+    pub boarding_progress: i64,
+    /// Action points left for this round
+    #[serde(default)]
+    pub action_points: i64,
+    /// Active autopilot order
+    #[serde(default)]
+    pub navigation: Option<Navigation>,
+    /// Active standing order
+    #[serde(default)]
+    pub order: Option<StandingOrder>,
+}
+
+#[derive(Clone, Debug, Deserialize)]
+pub struct Navigation {
+    pub target: Vec2D,
+    /// Followed asteroid, -1 if none
+    pub asteroid_id: i64,
+    /// Followed friendly ship, -1 if none
+    pub target_ship_id: i64,
+    pub speed: f64,
+    pub arrival_speed: f64,
+}
+
+/// Mining or hauling loop executed by the server every round until it is cancelled or impossible
+#[derive(Clone, Debug, Deserialize)]
+pub struct StandingOrder {
+    #[serde(rename = "type")]
+    pub order_type: OrderType,
+    /// Mined asteroid, -1 for ferry orders
+    pub asteroid_id: i64,
+    /// Ship the cargo is picked up from, -1 for mine orders
+    pub source_id: i64,
+    pub destination_id: ShipId,
+    pub resource: Resource,
+    pub amount: i64,
+    /// The ship is carrying cargo to the destination
+    pub unloading: bool,
+}
+
+#[derive(Clone, Copy, Debug, Serialize, Deserialize, PartialEq, Eq)]
+#[serde(rename_all = "lowercase")]
+pub enum OrderType {
+    Mine,
+    Ferry,
+}
+
+#[derive(Clone, Copy, Debug, Serialize, Deserialize, PartialEq, Eq)]
+#[serde(rename_all = "lowercase")]
+pub enum Resource {
+    Rock,
+    Fuel,
+}
+
+/// Reason why the server stopped executing a standing order of our ship
+#[derive(Clone, Debug, Deserialize)]
+pub struct OrderReport {
+    pub ship_id: ShipId,
+    #[serde(rename = "type")]
+    pub order_type: OrderType,
+    pub round: i64,
+    pub reason: String,
+}
+
+/// Transaction which was rolled back because one of its turns failed
+#[derive(Clone, Debug, Deserialize)]
+pub struct TransactionReport {
+    /// Position of the transaction in the turns we sent
+    pub index: usize,
+    /// Position of the failed turn within the transaction
+    pub turn: usize,
+    #[serde(rename = "type")]
+    pub turn_type: i64,
+    pub reason: String,
+}
+
+/// Message from another player, sent to us or to everybody last round
+#[derive(Clone, Debug, Deserialize)]
+pub struct Message {
+    pub round: i64,
+    pub from: PlayerId,
+    /// -1 for a broadcast
+    pub to: i64,
+    /// Any JSON value, usually a string
+    pub payload: serde_json::Value,
+}
+
+/// Sent with the first game state, describes the protocol and the rules of the match
+#[derive(Clone, Debug, Deserialize)]
+pub struct Handshake {
+    pub protocol_version: i64,
+    pub config: serde_json::Value,
+    /// Indexed by ship type
+    pub ships: Vec<ShipSpec>,
+    /// Action points spent by each turn type
+    pub action_costs: Vec<i64>,
+    /// Game rules enabled in this match
+    pub features: HashMap<String, bool>,
+    /// Protocol extensions we may ask for
+    pub capabilities: Vec<String>,
+}
+
+#[derive(Clone, Debug, Deserialize)]
+pub struct ShipSpec {
+    #[serde(rename = "type")]
+    pub ship_type: ShipType,
+    pub rock_price: i64,
+    pub health: i64,
+    pub action_points: i64,
+    /// Movement which costs no fuel
+    pub movement_free: f64,
+    /// Fuel paid per unit of movement above the free part
+    pub movement_multiplier: f64,
 }
 
 #[repr(u8)]
@@ -50,6 +179,14 @@ pub struct Asteroid {
     pub size: f64,
     pub owner_id: i64,
     pub surface: f64,
+    #[serde(default)]
+    pub is_wreck: bool,
+    #[serde(default = "Vec2D::zero", rename = "vector")]
+    pub velocity: Vec2D,
+    #[serde(default)]
+    pub weight: f64,
+    #[serde(default)]
+    pub damage: f64,
 }
 
 #[derive(Clone, Copy, Debug, Serialize, Deserialize, PartialEq, Eq, Hash)]
@@ -62,6 +199,85 @@ pub struct Wormhole {
     pub position: Vec2D,
 }
 
+#[derive(Clone, Copy, Debug, Serialize, Deserialize, PartialEq, Eq, Hash)]
+pub struct MineId(pub(super) usize);
+
+#[derive(Clone, Debug, Deserialize)]
+pub struct Mine {
+    pub id: MineId,
+    pub player: PlayerId,
+    pub position: Vec2D,
+}
+
+#[repr(u8)]
+#[derive(Clone, Debug, Deserialize_repr, PartialEq, Eq)]
+pub enum EventType {
+    SolarStorm,
+    MeteorShower,
+    IonCloud,
+}
+
+#[derive(Clone, Debug, Deserialize)]
+pub struct Event {
+    pub id: i64,
+    #[serde(rename = "type")]
+    pub event_type: EventType,
+    pub position: Vec2D,
+    /// 0 means the event covers the whole map
+    pub radius: f64,
+    pub start_round: i64,
+    pub end_round: i64,
+}
+
+impl Event {
+    pub fn is_active(&self, round: i64) -> bool {
+        self.start_round <= round && round <= self.end_round
+    }
+
+    pub fn covers(&self, position: &Vec2D) -> bool {
+        self.radius == 0.0 || self.position.distance(position) <= self.radius
+    }
+}
+
+#[derive(Clone, Debug, Deserialize)]
+pub struct Zone {
+    pub id: i64,
+    pub position: Vec2D,
+    pub radius: f64,
+    #[serde(rename = "vector")]
+    pub velocity: Vec2D,
+    /// -1 if nobody holds the zone
+    pub owner_id: i64,
+    pub contested: bool,
+}
+
+impl Zone {
+    pub fn contains(&self, position: &Vec2D) -> bool {
+        self.position.distance(position) <= self.radius
+    }
+}
+
+/// Named group of our ships, only our own fleets are sent to us
+#[derive(Clone, Debug, Deserialize)]
+pub struct Fleet {
+    pub name: String,
+    pub ship_ids: Vec<ShipId>,
+    pub formation: Formation,
+    pub spacing: f64,
+}
+
+#[derive(Clone, Copy, Debug, Default, Serialize, Deserialize, PartialEq, Eq)]
+#[serde(rename_all = "lowercase")]
+pub enum Formation {
+    #[default]
+    #[serde(rename = "")]
+    None,
+    Line,
+    Column,
+    Wedge,
+    Circle,
+}
+
 #[derive(Clone, Copy, Debug, Serialize, Deserialize, PartialEq, Eq, Hash)]
 pub struct PlayerId(pub(super) usize);
 
@@ -73,6 +289,11 @@ pub struct Player {
     pub mothership: Ship,
     pub alive: bool,
     pub score: i64,
+    pub team: i64,
+    #[serde(default)]
+    pub zone_score: i64,
+    /// Round in which the mothership was destroyed, -1 while the player is in the game
+    pub eliminated_round: i64,
 }
 
 #[derive(Clone, Debug, Deserialize)]
@@ -83,6 +304,12 @@ pub(super) struct GameMap {
     pub wormholes: Vec<Option<Wormhole>>,
     pub players: Vec<Option<Player>>,
     pub round: i64,
+    #[serde(default)]
+    pub events: Vec<Event>,
+    #[serde(default)]
+    pub mines: Vec<Option<Mine>>,
+    #[serde(default)]
+    pub zones: Vec<Zone>,
 }
 
 #[derive(Clone, Debug, Serialize)]
@@ -123,6 +350,216 @@ pub struct RepairTurn {
     pub ship_id: ShipId,
 }
 
+#[derive(Clone, Debug, Serialize)]
+pub struct MineTurn {
+    pub ship_id: ShipId,
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct CloakTurn {
+    pub ship_id: ShipId,
+    pub enabled: bool,
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct DecommissionTurn {
+    pub ship_id: ShipId,
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct AmmoTurn {
+    pub ship_id: ShipId,
+    pub amount: i64,
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct ShootAsteroidTurn {
+    pub source_id: ShipId,
+    pub asteroid_id: AsteroidId,
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct TowTurn {
+    pub ship_id: ShipId,
+    /// -1 releases the towed asteroid
+    pub asteroid_id: i64,
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct BoardTurn {
+    pub source_id: ShipId,
+    pub destination_id: ShipId,
+}
+
+/// Autopilot order, exactly one target should be set, no target cancels the current order
+#[derive(Clone, Debug, Serialize)]
+pub struct NavigateTurn {
+    pub ship_id: ShipId,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub target: Option<Vec2D>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub asteroid_id: Option<AsteroidId>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub target_ship_id: Option<ShipId>,
+    /// 0 uses the default cruise speed
+    #[serde(skip_serializing_if = "is_zero")]
+    pub speed: f64,
+    #[serde(skip_serializing_if = "is_zero")]
+    pub arrival_speed: f64,
+}
+
+fn is_zero(value: &f64) -> bool {
+    *value == 0.0
+}
+
+/// Creates or replaces the fleet, or disbands it
+#[derive(Clone, Debug, Serialize)]
+pub struct FleetTurn {
+    pub name: String,
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub ship_ids: Vec<ShipId>,
+    #[serde(skip_serializing_if = "is_no_formation")]
+    pub formation: Formation,
+    /// 0 uses the default spacing
+    #[serde(skip_serializing_if = "is_zero")]
+    pub spacing: f64,
+    #[serde(skip_serializing_if = "std::ops::Not::not")]
+    pub disband: bool,
+}
+
+fn is_no_formation(formation: &Formation) -> bool {
+    *formation == Formation::None
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct FleetMoveTurn {
+    pub fleet: String,
+    pub target: Vec2D,
+    #[serde(skip_serializing_if = "is_zero")]
+    pub speed: f64,
+    #[serde(skip_serializing_if = "is_zero")]
+    pub arrival_speed: f64,
+}
+
+#[derive(Clone, Debug, Serialize)]
+pub struct FleetAttackTurn {
+    pub fleet: String,
+    pub destination_id: ShipId,
+}
+
+/// Message with a JSON payload of at most 1024 bytes, no recipient sends it to everybody
+#[derive(Clone, Debug, Serialize)]
+pub struct MessageTurn {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub to: Option<PlayerId>,
+    pub payload: serde_json::Value,
+}
+
+/// Standing order, no order cancels the current one
+#[derive(Clone, Debug, Serialize)]
+pub struct OrderTurn {
+    pub ship_id: ShipId,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub order: Option<OrderType>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub asteroid_id: Option<AsteroidId>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub source_id: Option<ShipId>,
+    /// Mine orders unload at our mothership if not set
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub destination_id: Option<ShipId>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub resource: Option<Resource>,
+    /// 0 uses the default amount
+    #[serde(skip_serializing_if = "is_zero_amount")]
+    pub amount: i64,
+}
+
+fn is_zero_amount(value: &i64) -> bool {
+    *value == 0
+}
+
+/// Annotation drawn by the observer in the round it was sent, points can follow ships
+#[derive(Clone, Debug, Serialize)]
+pub struct DebugShape {
+    #[serde(rename = "type")]
+    pub shape_type: &'static str,
+    pub position: Vec2D,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub ship_id: Option<ShipId>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub to: Option<Vec2D>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub to_ship_id: Option<ShipId>,
+    #[serde(skip_serializing_if = "is_zero")]
+    pub radius: f64,
+    #[serde(skip_serializing_if = "String::is_empty")]
+    pub text: String,
+    /// CSS color, our player color if empty
+    #[serde(skip_serializing_if = "String::is_empty")]
+    pub color: String,
+}
+
+impl DebugShape {
+    fn new(shape_type: &'static str, position: Vec2D) -> DebugShape {
+        DebugShape {
+            shape_type,
+            position,
+            ship_id: None,
+            to: None,
+            to_ship_id: None,
+            radius: 0.0,
+            text: String::new(),
+            color: String::new(),
+        }
+    }
+
+    pub fn line(from: Vec2D, to: Vec2D) -> DebugShape {
+        DebugShape {
+            to: Some(to),
+            ..DebugShape::new("line", from)
+        }
+    }
+
+    pub fn circle(center: Vec2D, radius: f64) -> DebugShape {
+        DebugShape {
+            radius,
+            ..DebugShape::new("circle", center)
+        }
+    }
+
+    /// Label of at most 100 characters
+    pub fn text(position: Vec2D, text: &str) -> DebugShape {
+        DebugShape {
+            text: text.to_string(),
+            ..DebugShape::new("text", position)
+        }
+    }
+
+    /// The shape starts at (or is centered on) the ship instead of its position
+    pub fn on_ship(self, ship_id: ShipId) -> DebugShape {
+        DebugShape {
+            ship_id: Some(ship_id),
+            ..self
+        }
+    }
+
+    /// The line ends at the ship instead of its end position
+    pub fn to_ship(self, ship_id: ShipId) -> DebugShape {
+        DebugShape {
+            to_ship_id: Some(ship_id),
+            ..self
+        }
+    }
+
+    pub fn color(self, color: &str) -> DebugShape {
+        DebugShape {
+            color: color.to_string(),
+            ..self
+        }
+    }
+}
+
 #[derive(Clone, Debug)]
 pub enum Turn {
     BuyTurn(BuyTurn),
@@ -131,6 +568,21 @@ pub enum Turn {
     SiphonTurn(SiphonTurn),
     ShootTurn(ShootTurn),
     RepairTurn(RepairTurn),
+    MineTurn(MineTurn),
+    CloakTurn(CloakTurn),
+    DecommissionTurn(DecommissionTurn),
+    AmmoTurn(AmmoTurn),
+    ShootAsteroidTurn(ShootAsteroidTurn),
+    TowTurn(TowTurn),
+    BoardTurn(BoardTurn),
+    NavigateTurn(NavigateTurn),
+    FleetTurn(FleetTurn),
+    FleetMoveTurn(FleetMoveTurn),
+    FleetAttackTurn(FleetAttackTurn),
+    OrderTurn(OrderTurn),
+    /// Turns which either all succeed, or are all rolled back, transactions can't be nested
+    TransactionTurn(Vec<Turn>),
+    MessageTurn(MessageTurn),
 }
 
 impl Turn {
@@ -171,4 +623,190 @@ impl Turn {
     pub fn repair_turn(ship_id: ShipId) -> Turn {
         Turn::RepairTurn(RepairTurn { ship_id })
     }
+
+    pub fn mine_turn(ship_id: ShipId) -> Turn {
+        Turn::MineTurn(MineTurn { ship_id })
+    }
+
+    pub fn cloak_turn(ship_id: ShipId, enabled: bool) -> Turn {
+        Turn::CloakTurn(CloakTurn { ship_id, enabled })
+    }
+
+    pub fn decommission_turn(ship_id: ShipId) -> Turn {
+        Turn::DecommissionTurn(DecommissionTurn { ship_id })
+    }
+
+    pub fn ammo_turn(ship_id: ShipId, amount: i64) -> Turn {
+        Turn::AmmoTurn(AmmoTurn { ship_id, amount })
+    }
+
+    pub fn tow_turn(ship_id: ShipId, asteroid_id: AsteroidId) -> Turn {
+        Turn::TowTurn(TowTurn {
+            ship_id,
+            asteroid_id: asteroid_id.0 as i64,
+        })
+    }
+
+    pub fn release_tow_turn(ship_id: ShipId) -> Turn {
+        Turn::TowTurn(TowTurn {
+            ship_id,
+            asteroid_id: -1,
+        })
+    }
+
+    pub fn board_turn(source_id: ShipId, destination_id: ShipId) -> Turn {
+        Turn::BoardTurn(BoardTurn {
+            source_id,
+            destination_id,
+        })
+    }
+
+    pub fn navigate_to_position(ship_id: ShipId, target: Vec2D, arrival_speed: f64) -> Turn {
+        Turn::NavigateTurn(NavigateTurn {
+            ship_id,
+            target: Some(target),
+            asteroid_id: None,
+            target_ship_id: None,
+            speed: 0.0,
+            arrival_speed,
+        })
+    }
+
+    pub fn navigate_to_asteroid(ship_id: ShipId, asteroid_id: AsteroidId) -> Turn {
+        Turn::NavigateTurn(NavigateTurn {
+            ship_id,
+            target: None,
+            asteroid_id: Some(asteroid_id),
+            target_ship_id: None,
+            speed: 0.0,
+            arrival_speed: 0.0,
+        })
+    }
+
+    pub fn navigate_to_ship(ship_id: ShipId, target_ship_id: ShipId) -> Turn {
+        Turn::NavigateTurn(NavigateTurn {
+            ship_id,
+            target: None,
+            asteroid_id: None,
+            target_ship_id: Some(target_ship_id),
+            speed: 0.0,
+            arrival_speed: 0.0,
+        })
+    }
+
+    pub fn cancel_navigation_turn(ship_id: ShipId) -> Turn {
+        Turn::NavigateTurn(NavigateTurn {
+            ship_id,
+            target: None,
+            asteroid_id: None,
+            target_ship_id: None,
+            speed: 0.0,
+            arrival_speed: 0.0,
+        })
+    }
+
+    pub fn fleet_turn(
+        name: &str,
+        ship_ids: Vec<ShipId>,
+        formation: Formation,
+        spacing: f64,
+    ) -> Turn {
+        Turn::FleetTurn(FleetTurn {
+            name: name.to_string(),
+            ship_ids,
+            formation,
+            spacing,
+            disband: false,
+        })
+    }
+
+    pub fn disband_fleet_turn(name: &str) -> Turn {
+        Turn::FleetTurn(FleetTurn {
+            name: name.to_string(),
+            ship_ids: vec![],
+            formation: Formation::None,
+            spacing: 0.0,
+            disband: true,
+        })
+    }
+
+    pub fn fleet_move_turn(fleet: &str, target: Vec2D) -> Turn {
+        Turn::FleetMoveTurn(FleetMoveTurn {
+            fleet: fleet.to_string(),
+            target,
+            speed: 0.0,
+            arrival_speed: 0.0,
+        })
+    }
+
+    pub fn fleet_attack_turn(fleet: &str, destination_id: ShipId) -> Turn {
+        Turn::FleetAttackTurn(FleetAttackTurn {
+            fleet: fleet.to_string(),
+            destination_id,
+        })
+    }
+
+    pub fn mine_order_turn(ship_id: ShipId, asteroid_id: AsteroidId, amount: i64) -> Turn {
+        Turn::OrderTurn(OrderTurn {
+            ship_id,
+            order: Some(OrderType::Mine),
+            asteroid_id: Some(asteroid_id),
+            source_id: None,
+            destination_id: None,
+            resource: None,
+            amount,
+        })
+    }
+
+    pub fn ferry_order_turn(
+        ship_id: ShipId,
+        source_id: ShipId,
+        destination_id: ShipId,
+        resource: Resource,
+        amount: i64,
+    ) -> Turn {
+        Turn::OrderTurn(OrderTurn {
+            ship_id,
+            order: Some(OrderType::Ferry),
+            asteroid_id: None,
+            source_id: Some(source_id),
+            destination_id: Some(destination_id),
+            resource: Some(resource),
+            amount,
+        })
+    }
+
+    pub fn cancel_order_turn(ship_id: ShipId) -> Turn {
+        Turn::OrderTurn(OrderTurn {
+            ship_id,
+            order: None,
+            asteroid_id: None,
+            source_id: None,
+            destination_id: None,
+            resource: None,
+            amount: 0,
+        })
+    }
+
+    pub fn transaction_turn(turns: Vec<Turn>) -> Turn {
+        Turn::TransactionTurn(turns)
+    }
+
+    pub fn message_turn(to: PlayerId, payload: serde_json::Value) -> Turn {
+        Turn::MessageTurn(MessageTurn {
+            to: Some(to),
+            payload,
+        })
+    }
+
+    pub fn broadcast_turn(payload: serde_json::Value) -> Turn {
+        Turn::MessageTurn(MessageTurn { to: None, payload })
+    }
+
+    pub fn shoot_asteroid_turn(source_id: ShipId, asteroid_id: AsteroidId) -> Turn {
+        Turn::ShootAsteroidTurn(ShootAsteroidTurn {
+            source_id,
+            asteroid_id,
+        })
+    }
 }
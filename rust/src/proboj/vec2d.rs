@@ -43,6 +43,18 @@ impl Vec2D {
         }
     }
 
+    /// Distance from this point to the closest point of the segment a-b
+    pub fn distance_to_segment(&self, a: &Vec2D, b: &Vec2D) -> f64 {
+        let segment = *b - *a;
+        let length_squared = segment.x.powi(2) + segment.y.powi(2);
+        if length_squared == 0.0 {
+            return self.distance(a);
+        }
+        let t = ((self.x - a.x) * segment.x + (self.y - a.y) * segment.y) / length_squared;
+        let closest = *a + segment * t.clamp(0.0, 1.0);
+        self.distance(&closest)
+    }
+
     pub fn zero() -> Vec2D {
         Vec2D { x: 0.0, y: 0.0 }
     }
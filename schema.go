@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// UnmarshalJSON checks the shape of the container, but only remembers the error. Turns are
+// parsed one by one, so a single invalid turn doesn't throw away the rest of the response.
+func (c *TurnContainer) UnmarshalJSON(data []byte) error {
+	var container struct {
+		Type *TurnType       `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+
+	// Containers without a valid type are reported with type -1
+	c.Type = -1
+	c.err = decodeStrict(data, &container)
+	if c.err == nil && container.Type == nil {
+		c.err = fmt.Errorf("missing field \"type\"")
+	}
+	if c.err == nil {
+		c.Type = *container.Type
+		if len(container.Data) == 0 {
+			c.err = fmt.Errorf("missing field \"data\"")
+		}
+	}
+	c.Data = container.Data
+	return nil
+}
+
+// decodeTurnData decodes the data of a turn strictly. Unknown fields and missing fields
+// without omitempty are errors, so a typo doesn't silently turn into a zero value.
+func decodeTurnData(data json.RawMessage, v any) error {
+	if err := decodeStrict(data, v); err != nil {
+		return err
+	}
+	return checkRequiredFields(reflect.TypeOf(v), data, "")
+}
+
+func decodeStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return fmt.Errorf("field %q: expected %v, got %v", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	if err != nil {
+		return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+// checkRequiredFields walks the JSON along the Go type and returns an error naming
+// the first field which isn't marked omitempty and is missing
+func checkRequiredFields(t reflect.Type, data json.RawMessage, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// Types with their own decoding check themselves
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		// Values of a wrong type are reported by the decoder
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return nil
+		}
+		// null data would decode to a zero value
+		if fields == nil {
+			if path == "" {
+				return fmt.Errorf("missing field \"data\"")
+			}
+			return fmt.Errorf("missing field %q", strings.TrimSuffix(path, "."))
+		}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			value, ok := lookupField(fields, name)
+			if ok && isNullValue(field.Type, value) {
+				ok = false
+			}
+			if !ok {
+				if !strings.Contains(options, "omitempty") {
+					return fmt.Errorf("missing field %q", path+name)
+				}
+				continue
+			}
+			if isNull(value) {
+				// Decodes to nil, or keeps null as a value
+				continue
+			}
+			if err := checkRequiredFields(field.Type, value, path+name+"."); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%v[%d].", strings.TrimSuffix(path, "."), i)
+			if err := checkRequiredFields(t.Elem(), item, itemPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lookupField finds the field like encoding/json does, preferring an exact match of the name
+func lookupField(fields map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if value, ok := fields[name]; ok {
+		return value, true
+	}
+	for key, value := range fields {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// isNullValue reports whether the field is null although its type can't be nil. Such a null
+// would silently decode to a zero value, so it is treated like a missing field. Types with
+// their own decoding, like the raw message payload, keep null as a value.
+func isNullValue(t reflect.Type, value json.RawMessage) bool {
+	if t.Kind() == reflect.Pointer || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return false
+	}
+	return isNull(value)
+}
+
+func isNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
@@ -11,18 +11,29 @@ const (
 	TankerShip
 	TruckShip
 	BattleShip
+	ScoutShip
 )
 
 type Ship struct {
-	ID          int      `json:"id"`
-	PlayerID    int      `json:"player"`
-	Position    Position `json:"position"`
-	Vector      Position `json:"vector"`
-	Health      int      `json:"health"`
-	Fuel        float64  `json:"fuel"`
-	Type        ShipType `json:"type"`
-	Rock        int      `json:"rock"`
-	IsDestroyed bool     `json:"is_destroyed"`
+	ID               int            `json:"id"`
+	PlayerID         int            `json:"player"`
+	Position         Position       `json:"position"`
+	Vector           Position       `json:"vector"`
+	Health           int            `json:"health"`
+	Fuel             float64        `json:"fuel"`
+	Type             ShipType       `json:"type"`
+	Rock             int            `json:"rock"`
+	IsDestroyed      bool           `json:"is_destroyed"`
+	Cloaked          bool           `json:"cloaked"`
+	Cooldown         int            `json:"cooldown"` // Rounds until the ship can shoot again
+	Ammo             int            `json:"ammo"`
+	Heat             int            `json:"heat"`
+	TowingID         int            `json:"towing_id"`         // ID of the towed asteroid, -1 if none
+	BoardingID       int            `json:"boarding_id"`       // ID of the enemy ship being boarded, -1 if none
+	BoardingProgress int            `json:"boarding_progress"` // Rounds spent boarding the enemy ship
+	Navigation       *Navigation    `json:"navigation"`        // Active autopilot order, null if none
+	Order            *StandingOrder `json:"order"`             // Active standing order, null if none
+	ActionPoints     int            `json:"action_points"`     // Action points left for this round
 }
 
 func NewShip(m *Map, p *Player, shipType ShipType) *Ship {
@@ -30,10 +41,16 @@ func NewShip(m *Map, p *Player, shipType ShipType) *Ship {
 		ID:          len(m.Ships),
 		PlayerID:    p.ID,
 		Position:    p.MotherShip.Position,
-		Health:      ShipMaxHealth,
+		Health:      ShipHealth(shipType),
 		Fuel:        ShipStartFuel,
 		Type:        shipType,
 		IsDestroyed: false,
+		TowingID:    -1,
+		BoardingID:  -1,
+	}
+	s.ActionPoints = ShipActionPoints(shipType)
+	if shipType == BattleShip {
+		s.Ammo = ShipStartAmmo
 	}
 
 	m.Ships = append(m.Ships, s)
@@ -60,11 +77,138 @@ func DestroyShip(m *Map, ship *Ship) {
 	// Create asteroids from the ship's remains
 	NewAsteroidFromShip(m, ship, FuelAsteroid)
 	NewAsteroidFromShip(m, ship, RockAsteroid)
+
+	if ship.Type == MotherShip {
+		EliminatePlayer(m, m.Players[ship.PlayerID])
+	}
+}
+
+// DecommissionShip scraps the ship without leaving a wreck, returning its cargo
+// and part of its price to the owner's mothership
+func DecommissionShip(m *Map, p *Player, ship *Ship) {
+	m.runner.Log(fmt.Sprintf("Decommissioning ship %d (player %d, type %d)", ship.ID, ship.PlayerID, ship.Type))
+
+	p.MotherShip.Rock += ship.Rock + int(float64(ShipRockPrice(ship.Type))*DecommissionRefundRatio)
+	p.MotherShip.Fuel += ship.Fuel + ShipStartFuel*DecommissionRefundRatio
+
+	ship.Rock = 0
+	ship.Fuel = 0
+	ship.Health = 0
+	ship.IsDestroyed = true
+}
+
+// FireWeapon checks that the ship's weapon is ready and pays for a single shot
+func FireWeapon(ship *Ship) error {
+	if ship.Cooldown > 0 {
+		return fmt.Errorf("weapon of ship %v is cooling down for %v more rounds", ship.ID, ship.Cooldown)
+	}
+	if ship.Ammo <= 0 {
+		return fmt.Errorf("ship %v has no ammo", ship.ID)
+	}
+	if ship.Heat+ShipShotHeat > ShipMaxHeat {
+		return fmt.Errorf("ship %v is overheated: heat %v + %v > %v", ship.ID, ship.Heat, ShipShotHeat, ShipMaxHeat)
+	}
+
+	ship.Ammo--
+	ship.Cooldown = ShipShootCooldown
+	ship.Heat += ShipShotHeat
+	return nil
+}
+
+func HandleShipWeapons(ship *Ship) {
+	ship.Cooldown = max(ship.Cooldown-1, 0)
+	ship.Heat = max(ship.Heat-ShipHeatDissipation, 0)
+}
+
+// HandleShipTowing drags the towed asteroid along with the ship, keeping its offset from the ship.
+// The ship pays fuel based on its speed and the asteroid size, or lets go when it can't.
+func HandleShipTowing(m *Map, ship *Ship, previousPosition Position) {
+	if ship.TowingID == -1 {
+		return
+	}
+
+	asteroid := m.Asteroids[ship.TowingID]
+	if asteroid == nil {
+		ship.TowingID = -1
+		return
+	}
+
+	// Wormhole jumps are free, only the ship's own movement costs fuel
+	fuelCost := ship.Vector.Size() * asteroid.Size * TowFuelPerSize
+	if ship.Fuel < fuelCost {
+		m.runner.Log(fmt.Sprintf("Ship %d can't afford towing asteroid %d and lets it go", ship.ID, asteroid.ID))
+		ship.TowingID = -1
+		return
+	}
+
+	ship.Fuel -= fuelCost
+	offset := asteroid.Position.Sub(previousPosition)
+	asteroid.Position = ship.Position.Add(offset)
+}
+
+// CanBoard checks whether the ship can board the target
+func CanBoard(m *Map, ship *Ship, target *Ship) error {
+	if target == nil {
+		return fmt.Errorf("target ship does not exist")
+	}
+	if err := ValidateShipOperable(target); err != nil {
+		return fmt.Errorf("target ship %v: %v", target.ID, err)
+	}
+	if target.Type == MotherShip {
+		return fmt.Errorf("mothership can't be boarded")
+	}
+	if m.Allied(ship.PlayerID, target.PlayerID) {
+		return fmt.Errorf("target ship %v belongs to an ally", target.ID)
+	}
+	if target.Health > ShipBoardingHealthThreshold {
+		return fmt.Errorf("target ship %v is not damaged enough for boarding: %v > %v", target.ID, target.Health, ShipBoardingHealthThreshold)
+	}
+	distance := ship.Position.Distance(target.Position)
+	if distance > ShipBoardingDistance {
+		return fmt.Errorf("target ship %v too far for boarding: %v > %v", target.ID, distance, ShipBoardingDistance)
+	}
+	return nil
+}
+
+// HandleShipBoarding advances the boarding of an enemy ship, capturing it after ShipBoardingRounds.
+// Boarding is cancelled when the target stops being boardable, e.g. flies away or gets repaired.
+func HandleShipBoarding(m *Map, ship *Ship) {
+	if ship.BoardingID == -1 {
+		return
+	}
+
+	target := m.Ships[ship.BoardingID]
+	if err := CanBoard(m, ship, target); err != nil {
+		m.runner.Log(fmt.Sprintf("Ship %d stopped boarding: %v", ship.ID, err))
+		ship.BoardingID = -1
+		ship.BoardingProgress = 0
+		return
+	}
+
+	ship.BoardingProgress++
+	if ship.BoardingProgress >= ShipBoardingRounds {
+		CaptureShip(m, ship, target)
+		ship.BoardingID = -1
+		ship.BoardingProgress = 0
+	}
+}
+
+// CaptureShip hands the target over to the capturing ship's player together with its cargo.
+// The ship keeps its ID so bots can keep tracking it.
+func CaptureShip(m *Map, ship *Ship, target *Ship) {
+	m.runner.Log(fmt.Sprintf("Ship %d (player %d) captured by player %d", target.ID, target.PlayerID, ship.PlayerID))
+
+	target.PlayerID = ship.PlayerID
+	target.Cloaked = false
+	target.BoardingID = -1
+	target.BoardingProgress = 0
+	target.Navigation = nil
+	target.Order = nil
 }
 
 func CheckAndMarkDestroyedShips(m *Map) {
 	for _, ship := range m.Ships {
-		if ship != nil && !ship.IsDestroyed && ship.Health <= 0 && ship.Type != MotherShip {
+		if ship != nil && !ship.IsDestroyed && ship.Health <= 0 && (ship.Type != MotherShip || m.Config.Elimination) {
 			m.runner.Log(fmt.Sprintf("CheckAndMarkDestroyedShips: Ship %d (player %d) has %d health, marking for destruction",
 				ship.ID, ship.PlayerID, ship.Health))
 			DestroyShip(m, ship)
@@ -84,3 +228,35 @@ func ValidateShipOperable(ship *Ship) error {
 	}
 	return nil
 }
+
+// ShipVisibleTo reports whether the player can see the ship. Cloaked ships are hidden
+// from enemies unless one of their scouts is close enough to detect them.
+func ShipVisibleTo(m *Map, ship *Ship, p *Player) bool {
+	if !ship.Cloaked || m.Allied(ship.PlayerID, p.ID) {
+		return true
+	}
+
+	for _, scout := range m.Ships {
+		if scout == nil || scout.IsDestroyed || scout.Type != ScoutShip || !m.Allied(scout.PlayerID, p.ID) {
+			continue
+		}
+		if scout.Position.Distance(ship.Position) <= ScoutSensorRange {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleShipCloak charges the fuel needed to keep the ship cloaked, decloaking it when it runs out
+func HandleShipCloak(m *Map, ship *Ship) {
+	if !ship.Cloaked {
+		return
+	}
+
+	if ship.Fuel < ScoutCloakFuelCost {
+		m.runner.Log(fmt.Sprintf("Ship %d ran out of fuel and decloaked", ship.ID))
+		ship.Cloaked = false
+		return
+	}
+	ship.Fuel -= ScoutCloakFuelCost
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// TransactionError is returned by a transaction whose turn failed, after it has been rolled back
+type TransactionError struct {
+	Turn int      // Index of the failed turn within the transaction
+	Type TurnType // Type of the failed turn
+	Err  error
+}
+
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("turn %d (type %v) failed, transaction rolled back: %v", e.Turn, e.Type, e.Err)
+}
+
+func (e *TransactionError) Unwrap() error {
+	return e.Err
+}
+
+// TransactionReport tells the player which turn of a transaction failed
+type TransactionReport struct {
+	Index  int      `json:"index"` // Position of the transaction in the turns sent by the player
+	Turn   int      `json:"turn"`  // Position of the failed turn within the transaction
+	Type   TurnType `json:"type"`
+	Reason string   `json:"reason"`
+}
+
+// entitySnapshot remembers a list of entities and their values. Restoring it writes the values
+// back into the same structs, so pointers held elsewhere keep pointing to the restored entities.
+type entitySnapshot[T any] struct {
+	pointers []*T
+	values   []T
+}
+
+func snapshotEntities[T any](entities []*T) entitySnapshot[T] {
+	s := entitySnapshot[T]{pointers: slices.Clone(entities), values: make([]T, len(entities))}
+	for i, entity := range entities {
+		if entity != nil {
+			s.values[i] = *entity
+		}
+	}
+	return s
+}
+
+func (s entitySnapshot[T]) restore() []*T {
+	for i, entity := range s.pointers {
+		if entity != nil {
+			*entity = s.values[i]
+		}
+	}
+	return slices.Clone(s.pointers)
+}
+
+// MapSnapshot is the state turns can change: ships, asteroids, mines, players with their fleets and messages
+type MapSnapshot struct {
+	ships     entitySnapshot[Ship]
+	asteroids entitySnapshot[Asteroid]
+	mines     entitySnapshot[Mine]
+	players   entitySnapshot[Player]
+	messages  int
+	mineID    int
+}
+
+func SnapshotMap(m *Map) *MapSnapshot {
+	s := &MapSnapshot{
+		ships:     snapshotEntities(m.Ships),
+		asteroids: snapshotEntities(m.Asteroids),
+		mines:     snapshotEntities(m.Mines),
+		players:   snapshotEntities(m.Players),
+		messages:  len(m.Messages),
+		mineID:    m.nextMineID,
+	}
+
+	// Orders and fleets are changed in place, so they need their own copies
+	for i := range s.ships.values {
+		ship := &s.ships.values[i]
+		if ship.Navigation != nil {
+			navigation := *ship.Navigation
+			ship.Navigation = &navigation
+		}
+		if ship.Order != nil {
+			order := *ship.Order
+			ship.Order = &order
+		}
+	}
+	for i := range s.players.values {
+		player := &s.players.values[i]
+		player.Fleets = maps.Clone(player.Fleets)
+		for name, fleet := range player.Fleets {
+			clone := *fleet
+			clone.ShipIDs = slices.Clone(fleet.ShipIDs)
+			player.Fleets[name] = &clone
+		}
+		player.Reports = slices.Clone(player.Reports)
+		player.FailedTransactions = slices.Clone(player.FailedTransactions)
+	}
+
+	return s
+}
+
+// Restore rolls the map back to the snapshot. Entities created since then are dropped.
+func (s *MapSnapshot) Restore(m *Map) {
+	m.Ships = s.ships.restore()
+	m.Asteroids = s.asteroids.restore()
+	m.Mines = s.mines.restore()
+	m.Players = s.players.restore()
+	m.Messages = m.Messages[:s.messages]
+	m.nextMineID = s.mineID
+}
+
+// TransactionTurnData executes the turns as a single unit. If any of them fails, the effects
+// of all of them are rolled back and the failed turn is reported to the player.
+type TransactionTurnData struct {
+	Turns []TurnContainer `json:"turns"`
+}
+
+func (t TransactionTurnData) Execute(m *Map, p *Player) error {
+	snapshot := SnapshotMap(m)
+
+	for i, container := range t.Turns {
+		var err error
+		if container.Type == TransactionTurn {
+			err = fmt.Errorf("transactions can't be nested")
+		} else {
+			var turn Turn
+			turn, err = ParseTurnData(container)
+			if err == nil {
+				err = turn.Execute(m, p)
+			}
+		}
+
+		if err != nil {
+			snapshot.Restore(m)
+			return &TransactionError{Turn: i, Type: container.Type, Err: err}
+		}
+	}
+
+	return nil
+}
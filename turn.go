@@ -2,7 +2,9 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 )
 
 type TurnType int
@@ -14,59 +16,192 @@ const (
 	SiphonTurn
 	ShootTurn
 	RepairTurn
+	MineTurn
+	CloakTurn
+	DecommissionTurn
+	AmmoTurn
+	ShootAsteroidTurn
+	TowTurn
+	BoardTurn
+	NavigateTurn
+	FleetTurn
+	FleetMoveTurn
+	FleetAttackTurn
+	OrderTurn
+	TransactionTurn
+	MessageTurn
+
+	lastTurnType = MessageTurn // Keep pointing to the last turn type
 )
 
+// turnTypeNames are the names bots may send instead of the numbers, indexed by TurnType
+var turnTypeNames = []string{
+	"buy", "move", "load", "siphon", "shoot", "repair", "mine", "cloak", "decommission", "ammo",
+	"shoot_asteroid", "tow", "board", "navigate", "fleet", "fleet_move", "fleet_attack", "order",
+	"transaction", "message",
+}
+
+func (t TurnType) String() string {
+	if t < BuyTurn || t > lastTurnType {
+		return fmt.Sprintf("%d", int(t))
+	}
+	return turnTypeNames[t]
+}
+
+// UnmarshalJSON accepts the turn type either as a number or as its name
+func (t *TurnType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		if json.Unmarshal(data, (*int)(t)) != nil {
+			return fmt.Errorf("turn type must be a number or a name, got %s", data)
+		}
+		return nil
+	}
+
+	index := slices.Index(turnTypeNames, name)
+	if index == -1 {
+		return fmt.Errorf("unknown turn type: %q", name)
+	}
+	*t = TurnType(index)
+	return nil
+}
+
 type TurnContainer struct {
 	Type TurnType        `json:"type"`
 	Data json.RawMessage `json:"data"`
+	// Why the container itself is invalid. It is kept instead of failing the whole response,
+	// so that the other turns are still executed.
+	err error
 }
 
 func ParseTurnData(container TurnContainer) (Turn, error) {
+	if container.err != nil {
+		return nil, container.err
+	}
+
 	switch container.Type {
 	case BuyTurn:
 		var turn BuyTurnData
-		err := json.Unmarshal(container.Data, &turn)
+		err := decodeTurnData(container.Data, &turn)
 		return turn, err
 	case MoveTurn:
 		var turn MoveTurnData
-		err := json.Unmarshal(container.Data, &turn)
+		err := decodeTurnData(container.Data, &turn)
 		return turn, err
 	case LoadTurn:
 		var turn LoadTurnData
-		err := json.Unmarshal(container.Data, &turn)
+		err := decodeTurnData(container.Data, &turn)
 		return turn, err
 	case SiphonTurn:
 		var turn SiphonTurnData
-		err := json.Unmarshal(container.Data, &turn)
+		err := decodeTurnData(container.Data, &turn)
 		return turn, err
 	case ShootTurn:
 		var turn ShootTurnData
-		err := json.Unmarshal(container.Data, &turn)
+		err := decodeTurnData(container.Data, &turn)
 		return turn, err
 	case RepairTurn:
 		var turn RepairTurnData
-		err := json.Unmarshal(container.Data, &turn)
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case MineTurn:
+		var turn MineTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case CloakTurn:
+		var turn CloakTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case DecommissionTurn:
+		var turn DecommissionTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case AmmoTurn:
+		var turn AmmoTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case ShootAsteroidTurn:
+		var turn ShootAsteroidTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case TowTurn:
+		var turn TowTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case BoardTurn:
+		var turn BoardTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case NavigateTurn:
+		var turn NavigateTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case FleetTurn:
+		var turn FleetTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case FleetMoveTurn:
+		var turn FleetMoveTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case FleetAttackTurn:
+		var turn FleetAttackTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case OrderTurn:
+		var turn OrderTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case TransactionTurn:
+		var turn TransactionTurnData
+		err := decodeTurnData(container.Data, &turn)
+		return turn, err
+	case MessageTurn:
+		var turn MessageTurnData
+		err := decodeTurnData(container.Data, &turn)
 		return turn, err
 	}
 
 	return nil, fmt.Errorf("unknown turn type: %v", container.Type)
 }
 
-func useShip(m *Map, p *Player, shipID int) error {
-	if m.UsedShips[p.ID] == nil {
-		m.UsedShips[p.ID] = make(map[int]bool)
+// useShip spends the action points the turn costs. A ship can take several actions
+// in a round as long as it has enough points left.
+func useShip(m *Map, p *Player, shipID int, turnType TurnType) error {
+	ship := m.Ships[shipID]
+	if ship == nil {
+		return fmt.Errorf("invalid ship id: %v", shipID)
+	}
+
+	if ship.Cloaked {
+		return fmt.Errorf("ship %v is cloaked and can't perform actions", shipID)
 	}
 
-	if m.UsedShips[p.ID][shipID] {
-		return fmt.Errorf("ship %v has already been used by player %v this round", shipID, p.ID)
+	cost := TurnActionCost(turnType)
+	if ship.ActionPoints < cost {
+		return fmt.Errorf("ship %v of player %v has not enough action points: needed %v, has %v", shipID, p.ID, cost, ship.ActionPoints)
 	}
 
-	m.UsedShips[p.ID][shipID] = true
+	ship.ActionPoints -= cost
 	return nil
 }
 
+// canUseShip tells whether the ship has enough action points left for the turn
+func canUseShip(ship *Ship, turnType TurnType) bool {
+	return !ship.Cloaked && ship.ActionPoints >= TurnActionCost(turnType)
+}
+
+// ResetActionPoints gives every ship its full action point budget for the new round
+func ResetActionPoints(m *Map) {
+	for _, ship := range m.Ships {
+		if ship != nil {
+			ship.ActionPoints = ShipActionPoints(ship.Type)
+		}
+	}
+}
+
 func ExecuteTurns(m *Map, p *Player, turns []TurnContainer) {
-	for _, container := range turns {
+	for i, container := range turns {
 		turn, err := ParseTurnData(container)
 		if err != nil {
 			m.runner.Log(fmt.Sprintf("could not parse turn '%v': %v", container, err))
@@ -77,6 +212,16 @@ func ExecuteTurns(m *Map, p *Player, turns []TurnContainer) {
 		if err != nil {
 			m.runner.Log(fmt.Sprintf("error while executing turn '%v': %v", turn, err))
 		}
+
+		var transactionErr *TransactionError
+		if errors.As(err, &transactionErr) {
+			p.FailedTransactions = append(p.FailedTransactions, TransactionReport{
+				Index:  i,
+				Turn:   transactionErr.Turn,
+				Type:   transactionErr.Type,
+				Reason: transactionErr.Err.Error(),
+			})
+		}
 	}
 }
 
@@ -85,11 +230,11 @@ type Turn interface {
 }
 
 type BuyTurnData struct {
-	Type ShipType
+	Type ShipType `json:"type"`
 }
 
 func (t BuyTurnData) Execute(m *Map, p *Player) error {
-	if t.Type <= MotherShip || t.Type > BattleShip {
+	if t.Type <= MotherShip || t.Type > ScoutShip {
 		return fmt.Errorf("invalid ship type: %v", t.Type)
 	}
 
@@ -127,18 +272,29 @@ func (t MoveTurnData) Execute(m *Map, p *Player) error {
 		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
 	}
 
-	err := useShip(m, p, t.ShipID)
+	err := useShip(m, p, t.ShipID, MoveTurn)
 	if err != nil {
 		return err
 	}
 
-	if t.Vector.Size() > ShipMovementMaxSize {
-		scale := ShipMovementMaxSize / t.Vector.Size()
-		t.Vector.X *= scale
-		t.Vector.Y *= scale
+	// Manual control overrides the autopilot and standing orders
+	ship.Navigation = nil
+	ship.Order = nil
+	return applyMovement(m, p, ship, t.Vector)
+}
+
+// applyMovement adds the vector to the ship's movement vector and pays for it with fuel
+func applyMovement(m *Map, p *Player, ship *Ship, vector Position) error {
+	if vector.Size() > ShipMovementMaxSize {
+		scale := ShipMovementMaxSize / vector.Size()
+		vector.X *= scale
+		vector.Y *= scale
 	}
 
-	fuelCost := ShipMovementPrice(t.Vector, ship.Type)
+	fuelCost := ShipMovementPrice(vector, ship.Type)
+	if ActiveEventAt(m, ship.Position, IonCloud) != nil {
+		fuelCost *= IonCloudMovementMultiplier
+	}
 
 	// Mothership uses player fuel, other ships use their own fuel
 	if ship.Type == MotherShip {
@@ -153,7 +309,7 @@ func (t MoveTurnData) Execute(m *Map, p *Player) error {
 		ship.Fuel -= fuelCost
 	}
 
-	ship.Vector = ship.Vector.Add(t.Vector)
+	ship.Vector = ship.Vector.Add(vector)
 
 	return nil
 }
@@ -187,11 +343,11 @@ func (t LoadTurnData) Execute(m *Map, p *Player) error {
 	if source.PlayerID != p.ID {
 		return fmt.Errorf("source ship %v does not belong to player %v", t.SourceID, p.ID)
 	}
-	if destination.PlayerID != p.ID {
-		return fmt.Errorf("destination ship %v does not belong to player %v", t.DestinationID, p.ID)
+	if !m.Allied(destination.PlayerID, p.ID) {
+		return fmt.Errorf("destination ship %v does not belong to player %v or their allies", t.DestinationID, p.ID)
 	}
 
-	err := useShip(m, p, t.SourceID)
+	err := useShip(m, p, t.SourceID, LoadTurn)
 	if err != nil {
 		return err
 	}
@@ -227,10 +383,6 @@ func (t SiphonTurnData) Execute(m *Map, p *Player) error {
 	if t.Amount <= 0 {
 		return fmt.Errorf("amount must be positive: %v", t.Amount)
 	}
-	err := useShip(m, p, t.SourceID)
-	if err != nil {
-		return err
-	}
 
 	source := m.Ships[t.SourceID]
 	if err := ValidateShipOperable(source); err != nil {
@@ -250,8 +402,13 @@ func (t SiphonTurnData) Execute(m *Map, p *Player) error {
 	if source.PlayerID != p.ID {
 		return fmt.Errorf("source ship %v does not belong to player %v", t.SourceID, p.ID)
 	}
-	if destination.PlayerID != p.ID {
-		return fmt.Errorf("destination ship %v does not belong to player %v", t.DestinationID, p.ID)
+	if !m.Allied(destination.PlayerID, p.ID) {
+		return fmt.Errorf("destination ship %v does not belong to player %v or their allies", t.DestinationID, p.ID)
+	}
+
+	err := useShip(m, p, t.SourceID, SiphonTurn)
+	if err != nil {
+		return err
 	}
 
 	distance := source.Position.Distance(destination.Position)
@@ -281,10 +438,6 @@ func (t ShootTurnData) Execute(m *Map, p *Player) error {
 	if t.DestinationID < 0 || t.DestinationID >= len(m.Ships) {
 		return fmt.Errorf("invalid destination ship id: %v", t.DestinationID)
 	}
-	err := useShip(m, p, t.SourceID)
-	if err != nil {
-		return err
-	}
 
 	source := m.Ships[t.SourceID]
 	if err := ValidateShipOperable(source); err != nil {
@@ -301,16 +454,19 @@ func (t ShootTurnData) Execute(m *Map, p *Player) error {
 	if destination.Health <= 0 {
 		return fmt.Errorf("destination ship %v already has 0 health", t.DestinationID)
 	}
+	if !ShipVisibleTo(m, destination, p) {
+		return fmt.Errorf("destination ship %v is cloaked", t.DestinationID)
+	}
 
-	if source.PlayerID != p.ID {
-		return fmt.Errorf("source ship %v does not belong to player %v", t.SourceID, p.ID)
+	if err := checkShooter(m, p, source); err != nil {
+		return err
 	}
 
-	if source.Type != BattleShip {
-		return fmt.Errorf("source ship %v is not a BattleShip", t.SourceID)
+	if destination.PlayerID != p.ID && m.Allied(destination.PlayerID, p.ID) {
+		return fmt.Errorf("destination ship %v belongs to an ally", t.DestinationID)
 	}
 
-	if destination.Type == MotherShip {
+	if destination.Type == MotherShip && !m.Config.Elimination {
 		return fmt.Errorf("mothership is invincible")
 	}
 
@@ -321,10 +477,25 @@ func (t ShootTurnData) Execute(m *Map, p *Player) error {
 
 	destinationPlayer := m.Players[destination.PlayerID]
 	distanceToMothership := destination.Position.Distance(destinationPlayer.MotherShip.Position)
-	if distanceToMothership <= ShipRepairDistance {
+	if destination.Type != MotherShip && distanceToMothership <= ShipRepairDistance {
 		return fmt.Errorf("destination ship is protected near its mothership: %v <= %v", distanceToMothership, ShipRepairDistance)
 	}
 
+	err := useShip(m, p, t.SourceID, ShootTurn)
+	if err != nil {
+		return err
+	}
+
+	if err := FireWeapon(source); err != nil {
+		return err
+	}
+
+	// The shot was fired, so hitting cover isn't an error which would roll back a transaction
+	if blocker := checkLineOfSight(m, source.Position, destination.Position, nil); blocker != nil {
+		m.runner.Log(fmt.Sprintf("shot of ship %d blocked by asteroid %d", source.ID, blocker.ID))
+		return nil
+	}
+
 	destination.Health -= ShipShootDamage
 	if destination.Health <= 0 {
 		// Only destroy if not already destroyed to prevent double destruction
@@ -338,6 +509,40 @@ func (t ShootTurnData) Execute(m *Map, p *Player) error {
 	return nil
 }
 
+// checkShooter validates that the ship may fire its weapon this round
+func checkShooter(m *Map, p *Player, source *Ship) error {
+	if source.PlayerID != p.ID {
+		return fmt.Errorf("source ship %v does not belong to player %v", source.ID, p.ID)
+	}
+
+	if source.Type != BattleShip {
+		return fmt.Errorf("source ship %v is not a BattleShip", source.ID)
+	}
+
+	if event := ActiveEventAt(m, source.Position, IonCloud); event != nil {
+		return fmt.Errorf("source ship %v is inside ion cloud %v and cannot shoot", source.ID, event.ID)
+	}
+	return nil
+}
+
+// checkLineOfSight returns the asteroid which stopped the shot, if line of sight is enabled.
+// The targeted asteroid never blocks its own shot. In absorb mode the blocking asteroid
+// takes the damage instead of the target.
+func checkLineOfSight(m *Map, from, to Position, target *Asteroid) *Asteroid {
+	if m.Config.LineOfSight == "" {
+		return nil
+	}
+
+	blocker := FirstAsteroidOnLine(m, from, to)
+	if blocker == nil || blocker == target {
+		return nil
+	}
+	if m.Config.LineOfSight == "absorb" {
+		DamageAsteroid(m, blocker, ShipShootDamage)
+	}
+	return blocker
+}
+
 type RepairTurnData struct {
 	ShipID int `json:"ship_id"`
 }
@@ -354,7 +559,10 @@ func (t RepairTurnData) Execute(m *Map, p *Player) error {
 	if ship.PlayerID != p.ID {
 		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
 	}
-	err := useShip(m, p, t.ShipID)
+	if ship.Type == MotherShip && !m.Config.Elimination {
+		return fmt.Errorf("mothership can only be repaired in elimination mode")
+	}
+	err := useShip(m, p, t.ShipID, RepairTurn)
 	if err != nil {
 		return err
 	}
@@ -373,9 +581,630 @@ func (t RepairTurnData) Execute(m *Map, p *Player) error {
 	p.MotherShip.Rock -= ShipRepairRockCost
 
 	ship.Health += ShipRepairAmount
-	if ship.Health > ShipMaxHealth {
-		ship.Health = ShipMaxHealth
+	if ship.Health > ShipHealth(ship.Type) {
+		ship.Health = ShipHealth(ship.Type)
+	}
+
+	return nil
+}
+
+type MineTurnData struct {
+	ShipID int `json:"ship_id"`
+}
+
+func (t MineTurnData) Execute(m *Map, p *Player) error {
+	if t.ShipID < 0 || t.ShipID >= len(m.Ships) {
+		return fmt.Errorf("invalid ship id: %v", t.ShipID)
+	}
+
+	ship := m.Ships[t.ShipID]
+	if err := ValidateShipOperable(ship); err != nil {
+		return err
+	}
+	if ship.PlayerID != p.ID {
+		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
+	}
+	if ship.Type != BattleShip {
+		return fmt.Errorf("ship %v is not a BattleShip", t.ShipID)
+	}
+
+	err := useShip(m, p, t.ShipID, MineTurn)
+	if err != nil {
+		return err
+	}
+
+	if ship.Rock < MineRockCost {
+		return fmt.Errorf("insufficient rock in ship for mine: needed %v, has %v", MineRockCost, ship.Rock)
+	}
+
+	ship.Rock -= MineRockCost
+	NewMine(m, ship)
+	return nil
+}
+
+type CloakTurnData struct {
+	ShipID  int  `json:"ship_id"`
+	Enabled bool `json:"enabled"`
+}
+
+func (t CloakTurnData) Execute(m *Map, p *Player) error {
+	if t.ShipID < 0 || t.ShipID >= len(m.Ships) {
+		return fmt.Errorf("invalid ship id: %v", t.ShipID)
+	}
+
+	ship := m.Ships[t.ShipID]
+	if err := ValidateShipOperable(ship); err != nil {
+		return err
+	}
+	if ship.PlayerID != p.ID {
+		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
+	}
+	if ship.Type != ScoutShip {
+		return fmt.Errorf("ship %v is not a ScoutShip", t.ShipID)
+	}
+	if ship.Cloaked == t.Enabled {
+		return fmt.Errorf("ship %v already has cloak set to %v", t.ShipID, t.Enabled)
+	}
+
+	// Decloaking is the only action a cloaked ship may take, so the cloak has to go first.
+	// The ship stays cloaked if it can't pay for the action.
+	if !t.Enabled {
+		ship.Cloaked = false
+		if err := useShip(m, p, t.ShipID, CloakTurn); err != nil {
+			ship.Cloaked = true
+			return err
+		}
+		return nil
+	}
+
+	err := useShip(m, p, t.ShipID, CloakTurn)
+	if err != nil {
+		return err
+	}
+
+	if ship.Fuel < ScoutCloakFuelCost {
+		return fmt.Errorf("insufficient fuel for cloak: needed %v, has %v", ScoutCloakFuelCost, ship.Fuel)
+	}
+
+	ship.Cloaked = true
+	return nil
+}
+
+type DecommissionTurnData struct {
+	ShipID int `json:"ship_id"`
+}
+
+func (t DecommissionTurnData) Execute(m *Map, p *Player) error {
+	if t.ShipID < 0 || t.ShipID >= len(m.Ships) {
+		return fmt.Errorf("invalid ship id: %v", t.ShipID)
+	}
+
+	ship := m.Ships[t.ShipID]
+	if err := ValidateShipOperable(ship); err != nil {
+		return err
+	}
+	if ship.PlayerID != p.ID {
+		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
+	}
+	if ship.Type == MotherShip {
+		return fmt.Errorf("mothership can't be decommissioned")
+	}
+
+	err := useShip(m, p, t.ShipID, DecommissionTurn)
+	if err != nil {
+		return err
+	}
+
+	distance := ship.Position.Distance(p.MotherShip.Position)
+	if distance > ShipRepairDistance {
+		return fmt.Errorf("ship too far from mothership for decommission: %v > %v", distance, ShipRepairDistance)
+	}
+
+	DecommissionShip(m, p, ship)
+	return nil
+}
+
+type AmmoTurnData struct {
+	ShipID int `json:"ship_id"`
+	Amount int `json:"amount"`
+}
+
+func (t AmmoTurnData) Execute(m *Map, p *Player) error {
+	if t.ShipID < 0 || t.ShipID >= len(m.Ships) {
+		return fmt.Errorf("invalid ship id: %v", t.ShipID)
+	}
+	if t.Amount <= 0 {
+		return fmt.Errorf("amount must be positive: %v", t.Amount)
+	}
+
+	ship := m.Ships[t.ShipID]
+	if err := ValidateShipOperable(ship); err != nil {
+		return err
+	}
+	if ship.PlayerID != p.ID {
+		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
+	}
+	if ship.Type != BattleShip {
+		return fmt.Errorf("ship %v is not a BattleShip", t.ShipID)
+	}
+
+	err := useShip(m, p, t.ShipID, AmmoTurn)
+	if err != nil {
+		return err
+	}
+
+	distance := ship.Position.Distance(p.MotherShip.Position)
+	if distance > ShipRepairDistance {
+		return fmt.Errorf("ship too far from mothership for resupply: %v > %v", distance, ShipRepairDistance)
+	}
+
+	if ship.Ammo+t.Amount > ShipMaxAmmo {
+		return fmt.Errorf("ship can't carry that much ammo: %v + %v > %v", ship.Ammo, t.Amount, ShipMaxAmmo)
+	}
+
+	price := t.Amount * AmmoRockPrice
+	if p.MotherShip.Rock < price {
+		return fmt.Errorf("insufficient rock for ammo: needed %v, has %v", price, p.MotherShip.Rock)
+	}
+
+	p.MotherShip.Rock -= price
+	ship.Ammo += t.Amount
+	return nil
+}
+
+type ShootAsteroidTurnData struct {
+	SourceID   int `json:"source_id"`
+	AsteroidID int `json:"asteroid_id"`
+}
+
+func (t ShootAsteroidTurnData) Execute(m *Map, p *Player) error {
+	if t.SourceID < 0 || t.SourceID >= len(m.Ships) {
+		return fmt.Errorf("invalid source ship id: %v", t.SourceID)
+	}
+	if t.AsteroidID < 0 || t.AsteroidID >= len(m.Asteroids) {
+		return fmt.Errorf("invalid asteroid id: %v", t.AsteroidID)
+	}
+
+	source := m.Ships[t.SourceID]
+	if err := ValidateShipOperable(source); err != nil {
+		return fmt.Errorf("source ship %v: %v", t.SourceID, err)
+	}
+	asteroid := m.Asteroids[t.AsteroidID]
+	if asteroid == nil {
+		return fmt.Errorf("asteroid %v does not exist", t.AsteroidID)
+	}
+
+	if err := checkShooter(m, p, source); err != nil {
+		return err
+	}
+
+	err := useShip(m, p, t.SourceID, ShootAsteroidTurn)
+	if err != nil {
+		return err
+	}
+
+	distance := source.Position.Distance(asteroid.Position)
+	if distance > ShipShootDistance {
+		return fmt.Errorf("asteroid too far for shooting: %v > %v", distance, ShipShootDistance)
+	}
+
+	if err := FireWeapon(source); err != nil {
+		return err
+	}
+
+	// The shot was fired, so hitting cover isn't an error which would roll back a transaction
+	if blocker := checkLineOfSight(m, source.Position, asteroid.Position, asteroid); blocker != nil {
+		m.runner.Log(fmt.Sprintf("shot of ship %d blocked by asteroid %d", source.ID, blocker.ID))
+		return nil
+	}
+
+	DamageAsteroid(m, asteroid, ShipShootDamage)
+	return nil
+}
+
+type TowTurnData struct {
+	ShipID     int `json:"ship_id"`
+	AsteroidID int `json:"asteroid_id"` // -1 releases the towed asteroid
+}
+
+func (t TowTurnData) Execute(m *Map, p *Player) error {
+	if t.ShipID < 0 || t.ShipID >= len(m.Ships) {
+		return fmt.Errorf("invalid ship id: %v", t.ShipID)
+	}
+	if t.AsteroidID < -1 || t.AsteroidID >= len(m.Asteroids) {
+		return fmt.Errorf("invalid asteroid id: %v", t.AsteroidID)
+	}
+
+	ship := m.Ships[t.ShipID]
+	if err := ValidateShipOperable(ship); err != nil {
+		return err
+	}
+	if ship.PlayerID != p.ID {
+		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
+	}
+	if ship.Type != TruckShip {
+		return fmt.Errorf("ship %v is not a TruckShip", t.ShipID)
+	}
+
+	err := useShip(m, p, t.ShipID, TowTurn)
+	if err != nil {
+		return err
+	}
+
+	if t.AsteroidID == -1 {
+		ship.TowingID = -1
+		return nil
+	}
+
+	asteroid := m.Asteroids[t.AsteroidID]
+	if asteroid == nil {
+		return fmt.Errorf("asteroid %v does not exist", t.AsteroidID)
+	}
+
+	distance := ship.Position.Distance(asteroid.Position)
+	if distance > ShipTowDistance {
+		return fmt.Errorf("asteroid too far for towing: %v > %v", distance, ShipTowDistance)
+	}
+
+	for _, other := range m.Ships {
+		if other != nil && other != ship && !other.IsDestroyed && other.TowingID == asteroid.ID {
+			return fmt.Errorf("asteroid %v is already towed by ship %v", asteroid.ID, other.ID)
+		}
+	}
+
+	ship.TowingID = asteroid.ID
+	return nil
+}
+
+type BoardTurnData struct {
+	SourceID      int `json:"source_id"`
+	DestinationID int `json:"destination_id"`
+}
+
+func (t BoardTurnData) Execute(m *Map, p *Player) error {
+	if t.SourceID < 0 || t.SourceID >= len(m.Ships) {
+		return fmt.Errorf("invalid source ship id: %v", t.SourceID)
+	}
+	if t.DestinationID < 0 || t.DestinationID >= len(m.Ships) {
+		return fmt.Errorf("invalid destination ship id: %v", t.DestinationID)
+	}
+
+	source := m.Ships[t.SourceID]
+	if err := ValidateShipOperable(source); err != nil {
+		return fmt.Errorf("source ship %v: %v", t.SourceID, err)
+	}
+	if source.PlayerID != p.ID {
+		return fmt.Errorf("source ship %v does not belong to player %v", t.SourceID, p.ID)
+	}
+	if source.Type == MotherShip {
+		return fmt.Errorf("mothership can't board other ships")
+	}
+
+	destination := m.Ships[t.DestinationID]
+	if destination == nil {
+		return fmt.Errorf("destination ship %v does not exist", t.DestinationID)
+	}
+	if !ShipVisibleTo(m, destination, p) {
+		return fmt.Errorf("destination ship %v is cloaked", t.DestinationID)
+	}
+
+	err := useShip(m, p, t.SourceID, BoardTurn)
+	if err != nil {
+		return err
+	}
+
+	if err := CanBoard(m, source, destination); err != nil {
+		return err
+	}
+
+	source.BoardingID = destination.ID
+	source.BoardingProgress = 0
+	return nil
+}
+
+// NavigateTurnData gives the ship an autopilot order. Exactly one of the targets has to be set,
+// a turn without any target cancels the current order.
+type NavigateTurnData struct {
+	ShipID       int       `json:"ship_id"`
+	Target       *Position `json:"target,omitempty"`
+	AsteroidID   *int      `json:"asteroid_id,omitempty"`
+	TargetShipID *int      `json:"target_ship_id,omitempty"`
+	Speed        float64   `json:"speed,omitempty"`         // 0 means NavigationDefaultSpeed
+	ArrivalSpeed float64   `json:"arrival_speed,omitempty"` // 0 means the ship stops at the target
+}
+
+func (t NavigateTurnData) Execute(m *Map, p *Player) error {
+	if t.ShipID < 0 || t.ShipID >= len(m.Ships) {
+		return fmt.Errorf("invalid ship id: %v", t.ShipID)
+	}
+
+	ship := m.Ships[t.ShipID]
+	if err := ValidateShipOperable(ship); err != nil {
+		return err
+	}
+	if ship.PlayerID != p.ID {
+		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
+	}
+
+	targets := 0
+	n := &Navigation{AsteroidID: -1, ShipID: -1, Speed: NavigationDefaultSpeed, ArrivalSpeed: t.ArrivalSpeed}
+	if t.Target != nil {
+		n.Target = *t.Target
+		targets++
+	}
+	if t.AsteroidID != nil {
+		if *t.AsteroidID < 0 || *t.AsteroidID >= len(m.Asteroids) || m.Asteroids[*t.AsteroidID] == nil {
+			return fmt.Errorf("invalid target asteroid id: %v", *t.AsteroidID)
+		}
+		n.AsteroidID = *t.AsteroidID
+		targets++
+	}
+	if t.TargetShipID != nil {
+		if *t.TargetShipID < 0 || *t.TargetShipID >= len(m.Ships) || *t.TargetShipID == ship.ID {
+			return fmt.Errorf("invalid target ship id: %v", *t.TargetShipID)
+		}
+		n.ShipID = *t.TargetShipID
+		targets++
+	}
+	if targets > 1 {
+		return fmt.Errorf("navigation needs exactly one target, got %v", targets)
+	}
+
+	if targets == 1 {
+		if t.Speed < 0 || t.ArrivalSpeed < 0 {
+			return fmt.Errorf("navigation speeds can't be negative")
+		}
+		if t.Speed > 0 {
+			n.Speed = min(t.Speed, ShipMovementMaxSize)
+		}
+		if err := n.UpdateTarget(m, p); err != nil {
+			return err
+		}
+	} else {
+		// No target cancels the current navigation
+		n = nil
+	}
+
+	err := useShip(m, p, t.ShipID, NavigateTurn)
+	if err != nil {
+		return err
+	}
+
+	ship.Order = nil
+	ship.Navigation = n
+	return nil
+}
+
+// FleetTurnData creates or replaces the named fleet, or disbands it. It doesn't use the ships.
+type FleetTurnData struct {
+	Name      string    `json:"name"`
+	ShipIDs   []int     `json:"ship_ids,omitempty"`
+	Formation Formation `json:"formation,omitempty"`
+	Spacing   float64   `json:"spacing,omitempty"` // 0 means FleetDefaultSpacing
+	Disband   bool      `json:"disband,omitempty"`
+}
+
+func (t FleetTurnData) Execute(m *Map, p *Player) error {
+	if t.Name == "" {
+		return fmt.Errorf("fleet name can't be empty")
+	}
+
+	if t.Disband {
+		if p.Fleets[t.Name] == nil {
+			return fmt.Errorf("fleet %v does not exist", t.Name)
+		}
+		delete(p.Fleets, t.Name)
+		return nil
+	}
+
+	if p.Fleets[t.Name] == nil && len(p.Fleets) >= PlayerMaxFleets {
+		return fmt.Errorf("too many fleets: %v", PlayerMaxFleets)
+	}
+
+	switch t.Formation {
+	case NoFormation, LineFormation, ColumnFormation, WedgeFormation, CircleFormation:
+	default:
+		return fmt.Errorf("unknown formation: %v", t.Formation)
+	}
+	if t.Spacing < 0 {
+		return fmt.Errorf("fleet spacing can't be negative")
+	}
+
+	for _, id := range t.ShipIDs {
+		if id < 0 || id >= len(m.Ships) {
+			return fmt.Errorf("invalid ship id: %v", id)
+		}
+		if err := ValidateShipOperable(m.Ships[id]); err != nil {
+			return fmt.Errorf("ship %v: %v", id, err)
+		}
+		if m.Ships[id].PlayerID != p.ID {
+			return fmt.Errorf("ship %v does not belong to player %v", id, p.ID)
+		}
+	}
+
+	fleet := &Fleet{Name: t.Name, ShipIDs: []int{}, Formation: t.Formation, Spacing: t.Spacing}
+	if fleet.Spacing == 0 {
+		fleet.Spacing = FleetDefaultSpacing
+	}
+	delete(p.Fleets, t.Name)
+	for _, id := range t.ShipIDs {
+		RemoveFromFleets(p, id)
+		if !slices.Contains(fleet.ShipIDs, id) {
+			fleet.ShipIDs = append(fleet.ShipIDs, id)
+		}
+	}
+
+	p.Fleets[t.Name] = fleet
+	return nil
+}
+
+// FleetMoveTurnData sends every ship of the fleet to its place in the formation around the target.
+// It is expanded into a NavigateTurn for each ship.
+type FleetMoveTurnData struct {
+	Fleet        string   `json:"fleet"`
+	Target       Position `json:"target"`
+	Speed        float64  `json:"speed,omitempty"`
+	ArrivalSpeed float64  `json:"arrival_speed,omitempty"`
+}
+
+func (t FleetMoveTurnData) Execute(m *Map, p *Player) error {
+	fleet := p.Fleets[t.Fleet]
+	if fleet == nil {
+		return fmt.Errorf("fleet %v does not exist", t.Fleet)
+	}
+
+	direction := t.Target.Sub(fleet.Center(m))
+
+	var errs []error
+	for i, id := range fleet.ShipIDs {
+		target := t.Target.Add(fleet.FormationOffset(i, direction))
+		turn := NavigateTurnData{ShipID: id, Target: &target, Speed: t.Speed, ArrivalSpeed: t.ArrivalSpeed}
+		if err := turn.Execute(m, p); err != nil {
+			errs = append(errs, fmt.Errorf("ship %v: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FleetAttackTurnData makes every BattleShip of the fleet shoot at the target.
+// It is expanded into a ShootTurn for each BattleShip, other ships are left alone.
+type FleetAttackTurnData struct {
+	Fleet         string `json:"fleet"`
+	DestinationID int    `json:"destination_id"`
+}
+
+func (t FleetAttackTurnData) Execute(m *Map, p *Player) error {
+	fleet := p.Fleets[t.Fleet]
+	if fleet == nil {
+		return fmt.Errorf("fleet %v does not exist", t.Fleet)
+	}
+
+	var errs []error
+	shooters := 0
+	for _, id := range fleet.ShipIDs {
+		if m.Ships[id].Type != BattleShip {
+			continue
+		}
+
+		shooters++
+		turn := ShootTurnData{SourceID: id, DestinationID: t.DestinationID}
+		if err := turn.Execute(m, p); err != nil {
+			errs = append(errs, fmt.Errorf("ship %v: %w", id, err))
+		}
+	}
+
+	if shooters == 0 {
+		return fmt.Errorf("fleet %v has no BattleShips", t.Fleet)
+	}
+	return errors.Join(errs...)
+}
+
+// OrderTurnData gives the ship a standing order which the server executes every round, see StandingOrder.
+// A turn without an order cancels the current one.
+type OrderTurnData struct {
+	ShipID        int       `json:"ship_id"`
+	Order         OrderType `json:"order,omitempty"`
+	AsteroidID    *int      `json:"asteroid_id,omitempty"`    // Asteroid mined by a mine order
+	SourceID      *int      `json:"source_id,omitempty"`      // Ship a ferry order picks the cargo up from
+	DestinationID *int      `json:"destination_id,omitempty"` // Ship the cargo is unloaded to, mine orders default to the mothership
+	Resource      Resource  `json:"resource,omitempty"`       // Resource carried by a ferry order, rock by default
+	Amount        int       `json:"amount,omitempty"`         // 0 means OrderDefaultAmount
+}
+
+func (t OrderTurnData) Execute(m *Map, p *Player) error {
+	if t.ShipID < 0 || t.ShipID >= len(m.Ships) {
+		return fmt.Errorf("invalid ship id: %v", t.ShipID)
+	}
+
+	ship := m.Ships[t.ShipID]
+	if err := ValidateShipOperable(ship); err != nil {
+		return err
+	}
+	if ship.PlayerID != p.ID {
+		return fmt.Errorf("ship %v does not belong to player %v", t.ShipID, p.ID)
+	}
+
+	err := useShip(m, p, t.ShipID, OrderTurn)
+	if err != nil {
+		return err
+	}
+
+	if t.Order == "" {
+		ship.Order = nil
+		ship.Navigation = nil
+		return nil
+	}
+
+	if t.Amount < 0 {
+		return fmt.Errorf("amount can't be negative: %v", t.Amount)
+	}
+	order := &StandingOrder{Type: t.Order, AsteroidID: -1, SourceID: -1, Amount: t.Amount}
+	if order.Amount == 0 {
+		order.Amount = OrderDefaultAmount
+	}
+
+	switch t.Order {
+	case MineOrder:
+		if t.AsteroidID == nil || *t.AsteroidID < 0 || *t.AsteroidID >= len(m.Asteroids) || m.Asteroids[*t.AsteroidID] == nil {
+			return fmt.Errorf("mine order needs a valid asteroid id")
+		}
+		asteroid := m.Asteroids[*t.AsteroidID]
+		if !CheckAsteroidType(ship, asteroid) {
+			return fmt.Errorf("ship %v can't mine asteroid %v", ship.ID, asteroid.ID)
+		}
+		order.AsteroidID = asteroid.ID
+		order.Resource = RockResource
+		if asteroid.Type == FuelAsteroid {
+			order.Resource = FuelResource
+		}
+
+		order.DestinationID = p.MotherShip.ID
+		if t.DestinationID != nil {
+			order.DestinationID = *t.DestinationID
+		}
+	case FerryOrder:
+		if t.SourceID == nil || *t.SourceID < 0 || *t.SourceID >= len(m.Ships) || *t.SourceID == ship.ID {
+			return fmt.Errorf("ferry order needs a valid source ship id")
+		}
+		source := m.Ships[*t.SourceID]
+		if source.IsDestroyed || source.PlayerID != p.ID {
+			return fmt.Errorf("source ship %v does not belong to player %v", source.ID, p.ID)
+		}
+		if t.DestinationID == nil {
+			return fmt.Errorf("ferry order needs a destination ship id")
+		}
+		order.SourceID = source.ID
+		order.DestinationID = *t.DestinationID
+
+		order.Resource = t.Resource
+		if order.Resource == "" {
+			order.Resource = RockResource
+		}
+		if order.Resource != RockResource && order.Resource != FuelResource {
+			return fmt.Errorf("unknown resource: %v", t.Resource)
+		}
+		if order.Resource == FuelResource && ship.Type != MotherShip && ship.Type != TankerShip &&
+			source.Type != MotherShip && source.Type != TankerShip {
+			return fmt.Errorf("fuel transfer requires at least one ship to be MotherShip or TankerShip")
+		}
+	default:
+		return fmt.Errorf("unknown order: %v", t.Order)
+	}
+
+	if order.DestinationID < 0 || order.DestinationID >= len(m.Ships) ||
+		order.DestinationID == ship.ID || order.DestinationID == order.SourceID {
+		return fmt.Errorf("invalid destination ship id: %v", order.DestinationID)
+	}
+	destination, err := orderDestination(m, p, order)
+	if err != nil {
+		return err
+	}
+	if order.Resource == FuelResource && ship.Type != MotherShip && ship.Type != TankerShip &&
+		destination.Type != MotherShip && destination.Type != TankerShip {
+		return fmt.Errorf("fuel transfer requires at least one ship to be MotherShip or TankerShip")
 	}
 
+	ship.Order = order
+	ship.Navigation = nil
 	return nil
 }
@@ -11,9 +11,13 @@ type Wormhole struct {
 }
 
 func NewWormholes(m *Map) (*Wormhole, *Wormhole) {
+	return NewWormholesAt(m, RandomPosition(m), RandomPosition(m))
+}
+
+func NewWormholesAt(m *Map, a, b Position) (*Wormhole, *Wormhole) {
 	w1 := &Wormhole{
 		ID:       len(m.Wormholes),
-		Position: RandomPosition(m),
+		Position: a,
 	}
 
 	m.Wormholes = append(m.Wormholes, w1)
@@ -21,7 +25,7 @@ func NewWormholes(m *Map) (*Wormhole, *Wormhole) {
 	w2 := &Wormhole{
 		ID:       len(m.Wormholes),
 		TargetID: w1.ID,
-		Position: RandomPosition(m),
+		Position: b,
 	}
 
 	m.Wormholes = append(m.Wormholes, w2)
@@ -0,0 +1,87 @@
+package main
+
+import "math"
+
+// Zone is a king-of-the-hill control zone. Every round the player with the strongest
+// ship presence inside the zone earns ZonePoints, unless the zone is contested.
+type Zone struct {
+	ID        int      `json:"id"`
+	Position  Position `json:"position"`
+	Radius    float64  `json:"radius"`
+	Vector    Position `json:"vector"`
+	OwnerID   int      `json:"owner_id"`  // Player holding the zone this round, -1 if nobody
+	Contested bool     `json:"contested"` // Enemy presence is too strong for anybody to hold the zone
+}
+
+func NewZone(m *Map) *Zone {
+	z := &Zone{
+		ID:       len(m.Zones),
+		Position: RandomPositionInSector(m.Radius*ZoneSpawnRatio, 0, 2*math.Pi),
+		Radius:   ZoneRadius,
+		OwnerID:  -1,
+	}
+	if m.Config.ZoneSpeed > 0 {
+		z.Vector = RandomOffsetPosition(Position{}, 1).Normalize().Scale(m.Config.ZoneSpeed)
+	}
+
+	m.Zones = append(m.Zones, z)
+	return z
+}
+
+// ShipPresence is the strength a ship contributes to a zone. Motherships and cloaked ships don't count.
+func ShipPresence(ship *Ship) float64 {
+	if ship.IsDestroyed || ship.Cloaked || ship.Type == MotherShip {
+		return 0
+	}
+	return float64(ship.Health)
+}
+
+// UpdateZones moves the zones and awards points to the players holding them
+func UpdateZones(m *Map) {
+	for _, zone := range m.Zones {
+		MoveZone(m, zone)
+
+		presence := make([]float64, len(m.Players))
+		for _, ship := range m.Ships {
+			if ship != nil && ship.Position.Distance(zone.Position) <= zone.Radius {
+				presence[ship.PlayerID] += ShipPresence(ship)
+			}
+		}
+
+		zone.OwnerID = -1
+		zone.Contested = false
+
+		strongest := -1
+		for playerID, strength := range presence {
+			if strength > 0 && (strongest == -1 || strength > presence[strongest]) {
+				strongest = playerID
+			}
+		}
+		if strongest == -1 {
+			continue
+		}
+
+		for playerID, strength := range presence {
+			if !m.Allied(playerID, strongest) && strength*ZoneDominanceRatio > presence[strongest] {
+				zone.Contested = true
+			}
+		}
+		if zone.Contested {
+			continue
+		}
+
+		zone.OwnerID = strongest
+		m.Players[strongest].ZoneScore += ZonePoints
+	}
+}
+
+// MoveZone drifts the zone along its vector and bounces it off the map edges
+func MoveZone(m *Map, zone *Zone) {
+	zone.Position = zone.Position.Add(zone.Vector)
+	if math.Abs(zone.Position.X) > m.Radius-zone.Radius {
+		zone.Vector.X = -zone.Vector.X
+	}
+	if math.Abs(zone.Position.Y) > m.Radius-zone.Radius {
+		zone.Vector.Y = -zone.Vector.Y
+	}
+}